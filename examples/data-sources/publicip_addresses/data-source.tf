@@ -0,0 +1,5 @@
+data "publicip_addresses" "this" {}
+
+output "ipv6" {
+  value = data.publicip_addresses.this.ipv6.available ? data.publicip_addresses.this.ipv6.ip : "no IPv6"
+}
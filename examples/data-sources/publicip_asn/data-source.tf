@@ -0,0 +1,5 @@
+data "publicip_address" "default" {}
+
+data "publicip_asn" "upstream" {
+  ip = data.publicip_address.default.ip
+}
@@ -0,0 +1,3 @@
+data "publicip_cloud_ranges" "default" {
+  clouds = ["aws", "gcp", "cloudflare"] # optional
+}
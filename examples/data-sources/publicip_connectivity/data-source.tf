@@ -0,0 +1,5 @@
+data "publicip_connectivity" "default" {}
+
+output "ip" {
+  value = data.publicip_connectivity.default.has_ipv6 ? data.publicip_connectivity.default.ipv6 : data.publicip_connectivity.default.ipv4
+}
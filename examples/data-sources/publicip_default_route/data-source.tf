@@ -0,0 +1,5 @@
+data "publicip_default_route" "default" {}
+
+output "egress_interface" {
+  value = data.publicip_default_route.default.ipv4.interface
+}
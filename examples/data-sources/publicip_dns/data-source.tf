@@ -0,0 +1,4 @@
+data "publicip_dns" "default" {
+  method     = "opendns" # optional
+  ip_version = "v4"      # optional
+}
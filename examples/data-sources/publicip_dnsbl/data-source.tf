@@ -0,0 +1,3 @@
+data "publicip_dnsbl" "default" {
+  lists = ["zen.spamhaus.org", "bl.spamcop.net"] # optional
+}
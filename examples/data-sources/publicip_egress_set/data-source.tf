@@ -0,0 +1,4 @@
+data "publicip_egress_set" "default" {
+  samples  = 5    # optional
+  interval = "1s" # optional
+}
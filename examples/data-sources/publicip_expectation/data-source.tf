@@ -0,0 +1,3 @@
+data "publicip_expectation" "vpn" {
+  expected_cidrs = ["203.0.113.0/24", "2001:db8::/32"]
+}
@@ -0,0 +1,5 @@
+data "publicip_full" "this" {}
+
+output "behind_nat" {
+  value = data.publicip_full.this.is_behind_nat_v4
+}
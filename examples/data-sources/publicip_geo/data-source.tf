@@ -0,0 +1,15 @@
+variable "ipinfo_token" {
+  type      = string
+  sensitive = true
+}
+
+data "publicip_geo" "default" {
+  ip       = "203.0.113.4"        # optional
+  backend  = "ipinfo"             # optional
+  endpoint = "https://ipinfo.io/" # optional
+  api_key  = var.ipinfo_token     # optional
+}
+
+output "city" {
+  value = data.publicip_geo.default.city
+}
@@ -0,0 +1,3 @@
+data "publicip_hosts" "inventory" {
+  hostnames = ["example.com", "example.org"]
+}
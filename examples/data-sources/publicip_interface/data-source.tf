@@ -0,0 +1,7 @@
+data "publicip_interface" "eth0" {
+  name = "eth0"
+}
+
+data "publicip_address" "eth0" {
+  source_ip = [for address in data.publicip_interface.eth0.addresses : address.ip if address.scope == "global"][0]
+}
@@ -0,0 +1,7 @@
+data "publicip_local_addresses" "default" {}
+
+data "publicip_address" "by_source" {
+  for_each = toset(data.publicip_local_addresses.default.ipv4)
+
+  source_ip = each.value
+}
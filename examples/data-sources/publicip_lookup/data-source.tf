@@ -0,0 +1,3 @@
+data "publicip_lookup" "peer" {
+  ip = "2001:db8::1"
+}
@@ -0,0 +1,4 @@
+data "publicip_nat" "default" {
+  server        = "stun.stunprotocol.org:3478" # optional
+  probe_timeout = "2s"                         # optional
+}
@@ -0,0 +1,5 @@
+data "publicip_nat64" "default" {}
+
+output "nat64_prefix" {
+  value = data.publicip_nat64.default.prefix
+}
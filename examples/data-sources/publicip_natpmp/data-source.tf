@@ -0,0 +1,3 @@
+data "publicip_natpmp" "default" {
+  gateway = "192.168.1.1" # optional
+}
@@ -0,0 +1,9 @@
+data "publicip_port_check" "default" {
+  port     = 443
+  protocol = "tcp"                              # optional
+  endpoint = "https://ifconfig.co/port/{port}" # optional
+}
+
+output "https_reachable" {
+  value = data.publicip_port_check.default.reachable
+}
@@ -0,0 +1,3 @@
+data "publicip_prefix" "default" {
+  ip = "193.0.6.139" # optional
+}
@@ -0,0 +1,5 @@
+data "publicip_rdns" "mail" {}
+
+output "ptr" {
+  value = data.publicip_rdns.mail.names
+}
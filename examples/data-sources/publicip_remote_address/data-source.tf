@@ -0,0 +1,9 @@
+data "publicip_remote_address" "default" {
+  connection {
+    host          = "bastion.example.com"
+    port          = 22                                   # optional
+    user          = "terraform"                          # optional
+    identity_file = "~/.ssh/id_ed25519"                  # optional
+    options       = ["StrictHostKeyChecking=accept-new"] # optional
+  }
+}
@@ -0,0 +1,9 @@
+variable "abuseipdb_api_key" {
+  type      = string
+  sensitive = true
+}
+
+data "publicip_reputation" "default" {
+  service = "abuseipdb"
+  api_key = var.abuseipdb_api_key
+}
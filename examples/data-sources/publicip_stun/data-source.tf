@@ -0,0 +1,3 @@
+data "publicip_stun" "default" {
+  server = "stun.l.google.com:19302" # optional
+}
@@ -0,0 +1,3 @@
+data "publicip_upnp" "default" {
+  location = "http://192.168.1.1:5000/rootDesc.xml" # optional
+}
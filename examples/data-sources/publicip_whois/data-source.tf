@@ -0,0 +1,5 @@
+data "publicip_address" "default" {}
+
+data "publicip_whois" "default" {
+  ip = data.publicip_address.default.ip
+}
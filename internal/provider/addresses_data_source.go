@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type AddressesDataSource struct {
+	provider *ProviderModel
+}
+
+func NewAddressesDataSource() datasource.DataSource {
+	return &AddressesDataSource{}
+}
+
+func (d AddressesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_addresses"
+}
+
+func (d AddressesDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The current public IPv4 and IPv6 as reported by the IP information provider. Both are requested concurrently. A missing IP stack is reported in the respective object instead of failing the read, unless both are missing.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ipv4": {
+				MarkdownDescription: "The public IPv4.",
+				Computed:            true,
+				Attributes:          tfsdk.SingleNestedAttributes(addressFamilyAttributes()),
+			},
+			"ipv6": {
+				MarkdownDescription: "The public IPv6.",
+				Computed:            true,
+				Attributes:          tfsdk.SingleNestedAttributes(addressFamilyAttributes()),
+			},
+		},
+	}, nil
+}
+
+func addressFamilyAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"available": {
+			MarkdownDescription: "`true` if the IP information provider could be reached over this IP stack.",
+			Computed:            true,
+			Type:                types.BoolType,
+		},
+		"error": {
+			MarkdownDescription: "Why the IP information provider could not be reached over this IP stack. `null` if it is `available`.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"ip": {
+			MarkdownDescription: "The IP as returned by the IP information provider. `null` if it is not `available`.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"ip_url_host": {
+			MarkdownDescription: "The IP in a form that can be used as host in a URL, i.e. IPv6 addresses are enclosed in brackets, e.g. `[2001:db8::1]`.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"scope": {
+			MarkdownDescription: fmt.Sprintf("The scope of the IP. Expected values: '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s'", IPScopeGlobal, IPScopePrivate, IPScopeUniqueLocal, IPScopeLinkLocal, IPScopeLoopback, IPScopeMulticast, IPScopeUnspecified, IPUnknown),
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"asn_id": {
+			MarkdownDescription: "The ASN as returned by the IP information provider.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"asn_org": {
+			MarkdownDescription: "The organisation to which the ASN is registered to as returned by the IP information provider.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"country": {
+			MarkdownDescription: "The country as returned by the IP information provider.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"country_iso": {
+			MarkdownDescription: "The ISO code of the country as returned by the IP information provider.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"city": {
+			MarkdownDescription: "The city as returned by the IP information provider.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+	}
+}
+
+func (d *AddressesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type AddressesDataSourceModel struct {
+	ID   types.String        `tfsdk:"id"`
+	IPv4 *AddressFamilyModel `tfsdk:"ipv4"`
+	IPv6 *AddressFamilyModel `tfsdk:"ipv6"`
+}
+
+type AddressFamilyModel struct {
+	Available  types.Bool   `tfsdk:"available"`
+	Error      types.String `tfsdk:"error"`
+	IP         types.String `tfsdk:"ip"`
+	IPURLHost  types.String `tfsdk:"ip_url_host"`
+	Scope      types.String `tfsdk:"scope"`
+	ASNID      types.String `tfsdk:"asn_id"`
+	ASNOrg     types.String `tfsdk:"asn_org"`
+	Country    types.String `tfsdk:"country"`
+	CountryISO types.String `tfsdk:"country_iso"`
+	City       types.String `tfsdk:"city"`
+}
+
+func (d AddressesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data AddressesDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	log.Printf("looking up the IPv4 and the IPv6 🔍")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		data.IPv4 = d.lookupFamily(ctx, "tcp4")
+	}()
+	go func() {
+		defer wg.Done()
+		data.IPv6 = d.lookupFamily(ctx, "tcp6")
+	}()
+	wg.Wait()
+
+	var ids []string
+	for _, family := range []*AddressFamilyModel{data.IPv4, data.IPv6} {
+		if family.Available.Value {
+			ids = append(ids, family.IP.Value)
+		}
+	}
+	if len(ids) == 0 {
+		noPublicIP(&resp.Diagnostics, fmt.Sprintf("IPv4: %s\nIPv6: %s", data.IPv4.Error.Value, data.IPv6.Error.Value))
+		return
+	}
+	data.ID = types.String{Value: strings.Join(ids, ",")}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookupFamily fetches the public IP over the given network.
+// Errors are reported in the returned model rather than as diagnostics, as the IP stack may be missing.
+func (d AddressesDataSource) lookupFamily(ctx context.Context, network string) *AddressFamilyModel {
+	unavailable := func(reason string) *AddressFamilyModel {
+		log.Printf("'%s' unavailable 🚨: %s", network, reason)
+		return &AddressFamilyModel{
+			Available:  types.Bool{Value: false},
+			Error:      types.String{Value: reason},
+			IP:         types.String{Null: true},
+			IPURLHost:  types.String{Null: true},
+			Scope:      types.String{Null: true},
+			ASNID:      types.String{Null: true},
+			ASNOrg:     types.String{Null: true},
+			Country:    types.String{Null: true},
+			CountryISO: types.String{Null: true},
+			City:       types.String{Null: true},
+		}
+	}
+
+	result, diags := fetchIPOverNetwork(ctx, d.provider, network)
+	if diags.HasError() {
+		var reasons []string
+		for _, e := range diags.Errors() {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", e.Summary(), e.Detail()))
+		}
+		return unavailable(strings.Join(reasons, "\n"))
+	}
+
+	ip := result.ip
+
+	respData := result.respData
+	return &AddressFamilyModel{
+		Available:  types.Bool{Value: true},
+		Error:      types.String{Null: true},
+		IP:         types.String{Value: ip.String()},
+		IPURLHost:  types.String{Value: ipURLHost(ip)},
+		Scope:      types.String{Value: ipScope(ip)},
+		ASNID:      types.String{Value: respData.ASN},
+		ASNOrg:     types.String{Value: respData.ASNOrg},
+		Country:    types.String{Value: respData.Country},
+		CountryISO: types.String{Value: respData.CountryISO},
+		City:       types.String{Value: respData.City},
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestAddressesDataSourceDualStack(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, _ = fmt.Fprintf(w, `{"ip":"%s","asn":"AS64496"}`, host)
+	}, "127.0.0.1", "::1")
+
+	resp := testReadDataSource(t, NewAddressesDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data AddressesDataSourceModel
+	resp.State.Get(context.Background(), &data)
+
+	if !data.IPv4.Available.Value || data.IPv4.IP.Value != "127.0.0.1" || !data.IPv4.Error.Null || data.IPv4.ASNID.Value != "AS64496" {
+		t.Errorf("unexpected ipv4: %+v", data.IPv4)
+	}
+	if !data.IPv6.Available.Value || data.IPv6.IP.Value != "::1" || data.IPv6.IPURLHost.Value != "[::1]" {
+		t.Errorf("unexpected ipv6: %+v", data.IPv6)
+	}
+}
+
+func TestAddressesDataSourceIPv4Only(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"203.0.113.4"}`)
+	}, "127.0.0.1")
+
+	resp := testReadDataSource(t, NewAddressesDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data AddressesDataSourceModel
+	resp.State.Get(context.Background(), &data)
+
+	if !data.IPv4.Available.Value || data.IPv4.IP.Value != "203.0.113.4" {
+		t.Errorf("unexpected ipv4: %+v", data.IPv4)
+	}
+	if data.IPv6.Available.Value || data.IPv6.Error.Value == "" || !data.IPv6.IP.Null {
+		t.Errorf("expected ipv6 to be unavailable: %+v", data.IPv6)
+	}
+	if data.ID.Value != "203.0.113.4" {
+		t.Errorf("unexpected id '%s'", data.ID.Value)
+	}
+}
+
+func TestAddressesDataSourceUnavailable(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"203.0.113.4"}`)
+	})
+
+	resp := testReadDataSource(t, NewAddressesDataSource, providerData, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error without any public IP")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "No public IP" {
+		t.Errorf("expected the same error as publicip_full, got '%s'", summary)
+	}
+}
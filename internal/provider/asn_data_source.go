@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+// The IP to ASN mapping of Team Cymru, see https://www.team-cymru.com/ip-asn-mapping
+const cymruOriginZone = "origin.asn.cymru.com"
+const cymruOrigin6Zone = "origin6.asn.cymru.com"
+const cymruASNZone = "asn.cymru.com"
+
+type ASNDataSource struct {
+	// lookupTXT resolves the TXT records of the Team Cymru DNS zones.
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	provider  *ProviderModel
+}
+
+func NewASNDataSource() datasource.DataSource {
+	return &ASNDataSource{lookupTXT: net.DefaultResolver.LookupTXT}
+}
+
+func (d ASNDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_asn"
+}
+
+func (d ASNDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Maps an arbitrary IP to the ASN, which originates it, using the DNS service of [Team Cymru](https://www.team-cymru.com/ip-asn-mapping). The IP information provider is not involved.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to map to its ASN.",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"asn_id": {
+				MarkdownDescription: "The ASN, which originates the IP, e.g. `AS64496`. If the prefix is originated by multiple ASNs, the first one is used.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"asn_org": {
+				MarkdownDescription: "The organisation to which the ASN is registered to.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"prefix": {
+				MarkdownDescription: "The announced prefix, which contains the IP, e.g. `203.0.113.0/24`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country_iso": {
+				MarkdownDescription: "The ISO code of the country, in which the prefix is registered.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"registry": {
+				MarkdownDescription: "The regional internet registry, at which the prefix is registered, e.g. `ripencc`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+type ASNDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	IP         types.String `tfsdk:"ip"`
+	ASNID      types.String `tfsdk:"asn_id"`
+	ASNOrg     types.String `tfsdk:"asn_org"`
+	Prefix     types.String `tfsdk:"prefix"`
+	CountryISO types.String `tfsdk:"country_iso"`
+	Registry   types.String `tfsdk:"registry"`
+}
+
+func (d *ASNDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+func (d ASNDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data ASNDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, err := netaddr.ParseIP(data.IP.Value)
+	if err != nil {
+		log.Printf("Could not parse IP '%s' 🚨: %s", data.IP.Value, err)
+		resp.Diagnostics.AddError("Invalid IP", fmt.Sprintf("The ip '%s' could not be parsed: %s", data.IP.Value, err))
+		return
+	}
+
+	origin, err := d.lookupCymru(ctx, cymruOriginName(ip))
+	if err != nil {
+		resp.Diagnostics.AddError("Error mapping the IP to its ASN", fmt.Sprintf("The origin of the IP '%s' could not be looked up: %s", ip, err))
+		return
+	}
+	// A prefix may be originated by several ASNs, e.g. `64496 64497`.
+	var asns []string
+	if len(origin) >= 4 {
+		asns = strings.Fields(origin[0])
+	}
+	if len(asns) == 0 {
+		resp.Diagnostics.AddError("Error mapping the IP to its ASN", fmt.Sprintf("The origin of the IP '%s' has an unexpected format: %v", ip, origin))
+		return
+	}
+	asn := asns[0]
+
+	description, err := d.lookupCymru(ctx, "AS"+asn+"."+cymruASNZone)
+	if err != nil {
+		resp.Diagnostics.AddError("Error mapping the IP to its ASN", fmt.Sprintf("The organisation of the ASN 'AS%s' could not be looked up: %s", asn, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.ASNID = types.String{Value: "AS" + asn}
+	data.ASNOrg = types.String{Null: true}
+	if len(description) >= 5 {
+		data.ASNOrg = types.String{Value: description[4]}
+	}
+	data.Prefix = types.String{Value: origin[1]}
+	data.CountryISO = types.String{Value: origin[2]}
+	data.Registry = types.String{Value: origin[3]}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookupCymru resolves the TXT record of name and splits it into its `|`-separated fields.
+func (d ASNDataSource) lookupCymru(ctx context.Context, name string) ([]string, error) {
+	log.Printf("looking up the ASN 🔍: %s", name)
+
+	records, err := d.lookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no TXT record for '%s'", name)
+	}
+
+	fields := strings.Split(records[0], "|")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields, nil
+}
+
+// cymruOriginName returns the DNS name to look up the origin of the IP,
+// i.e. the reversed octets of an IPv4 or the reversed nibbles of an IPv6.
+func cymruOriginName(ip netaddr.IP) string {
+	if ip.Is4() || ip.Is4in6() {
+		return reverseDNSLabels(ip) + "." + cymruOriginZone
+	}
+	return reverseDNSLabels(ip) + "." + cymruOrigin6Zone
+}
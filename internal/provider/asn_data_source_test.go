@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+func TestCymruOriginName(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.4":          "4.113.0.203.origin.asn.cymru.com",
+		"::ffff:203.0.113.4":   "4.113.0.203.origin.asn.cymru.com",
+		"2001:db8::1":          "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.origin6.asn.cymru.com",
+		"2001:db8:1234:5678::": "0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.7.6.5.4.3.2.1.8.b.d.0.1.0.0.2.origin6.asn.cymru.com",
+	}
+
+	for address, expected := range tests {
+		if actual := cymruOriginName(netaddr.MustParseIP(address)); actual != expected {
+			t.Errorf("expected '%s' for '%s', got '%s'", expected, address, actual)
+		}
+	}
+}
+
+func TestASNDataSource(t *testing.T) {
+	records := map[string]string{
+		"4.113.0.203.origin.asn.cymru.com": "64496 64497 | 203.0.113.0/24 | CH | ripencc | 2001-01-01",
+		"AS64496.asn.cymru.com":            "64496 | CH | ripencc | 2001-01-01 | EXAMPLE-AS Example AG, CH",
+		"4.2.0.192.origin.asn.cymru.com":   " | 192.0.2.0/24 | CH | ripencc | 2001-01-01",
+	}
+	newDataSource := func() datasource.DataSource {
+		return &ASNDataSource{lookupTXT: func(_ context.Context, name string) ([]string, error) {
+			if record, ok := records[name]; ok {
+				return []string{record}, nil
+			}
+			return nil, fmt.Errorf("no such host '%s'", name)
+		}}
+	}
+
+	resp := testReadDataSource(t, newDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "203.0.113.4"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data ASNDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.ASNID.Value != "AS64496" || data.ASNOrg.Value != "EXAMPLE-AS Example AG, CH" {
+		t.Errorf("unexpected ASN: %+v", data)
+	}
+	if data.Prefix.Value != "203.0.113.0/24" || data.CountryISO.Value != "CH" || data.Registry.Value != "ripencc" {
+		t.Errorf("unexpected prefix: %+v", data)
+	}
+
+	resp = testReadDataSource(t, newDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "198.51.100.1"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for an unannounced IP")
+	}
+
+	resp = testReadDataSource(t, newDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "192.0.2.4"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for an origin without ASN")
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const DefaultRDAPURL = "https://rdap.org/"
+
+// euCountries are the ISO 3166-1 alpha-2 codes of the member states of the European Union.
+var euCountries = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CY": true, "CZ": true, "DE": true, "DK": true,
+	"EE": true, "ES": true, "FI": true, "FR": true, "GR": true, "HR": true, "HU": true,
+	"IE": true, "IT": true, "LT": true, "LU": true, "LV": true, "MT": true, "NL": true,
+	"PL": true, "PT": true, "RO": true, "SE": true, "SI": true, "SK": true,
+}
+
+func isEUCountry(countryISO string) bool {
+	return euCountries[strings.ToUpper(countryISO)]
+}
+
+// lookupASNCountry looks up the country, in which the ASN is registered, using RDAP (RFC 9083).
+// The ASN may be given with or without the `AS` prefix, e.g. `AS3303` or `3303`.
+// An empty string is returned if the registry does not state a country.
+func lookupASNCountry(ctx context.Context, p *ProviderModel, client *http.Client, asn string) (string, error) {
+	number := strings.TrimPrefix(strings.ToUpper(asn), "AS")
+	if number == "" {
+		return "", fmt.Errorf("no ASN to look up")
+	}
+
+	lookupURL := *p.rdapURL
+	lookupURL.Path = path.Join(lookupURL.Path, "autnum", url.PathEscape(number))
+
+	log.Printf("looking up the ASN 🔍: %s", lookupURL.String())
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", lookupURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+	req.Header.Set("User-Agent", p.nextUserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the RDAP server responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	var autnum struct {
+		Country string `json:"country"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes)).Decode(&autnum)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("got ASN country ✅: %s: '%s'", asn, autnum.Country)
+	return autnum.Country, nil
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// bindDeviceSupported tells whether the connections can be bound to a network device on this platform.
+const bindDeviceSupported = true
+
+// bindToDevice returns a control function for the net.Dialer, which binds the socket to the network device using
+// SO_BINDTODEVICE. The traffic then leaves through that device, regardless of the routing policy.
+func bindToDevice(device string) func(network, address string, c syscall.RawConn) error {
+	return func(_, _ string, c syscall.RawConn) error {
+		var bindErr error
+		err := c.Control(func(fd uintptr) {
+			bindErr = syscall.BindToDevice(int(fd), device)
+		})
+		if err != nil {
+			return err
+		}
+		if bindErr != nil {
+			return fmt.Errorf("the socket could not be bound to the device '%s': %w", device, bindErr)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package provider
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// bindDeviceSupported tells whether the connections can be bound to a network device on this platform.
+const bindDeviceSupported = false
+
+// bindToDevice is only supported on Linux, where SO_BINDTODEVICE is available.
+func bindToDevice(device string) func(network, address string, c syscall.RawConn) error {
+	return func(_, _ string, _ syscall.RawConn) error {
+		return fmt.Errorf("the socket can't be bound to the device '%s', because this is only supported on Linux", device)
+	}
+}
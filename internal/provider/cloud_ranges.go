@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"inet.af/netaddr"
+)
+
+// The clouds, whose IP range feeds are supported.
+const (
+	CloudAWS        = "aws"
+	CloudGCP        = "gcp"
+	CloudAzure      = "azure"
+	CloudCloudflare = "cloudflare"
+)
+
+// DefaultCloudFeedURLs are the published IP range feeds.
+// Azure publishes its service tags under a URL, which changes weekly, so its feed URL must be configured.
+var DefaultCloudFeedURLs = map[string]string{
+	CloudAWS:        "https://ip-ranges.amazonaws.com/ip-ranges.json",
+	CloudGCP:        "https://www.gstatic.com/ipranges/cloud.json",
+	CloudCloudflare: "https://api.cloudflare.com/client/v4/ips",
+}
+
+// cloudFeedParsers parse the feed of each cloud into its ranges.
+var cloudFeedParsers = map[string]func([]byte) ([]cloudRange, error){
+	CloudAWS:        parseAWSRanges,
+	CloudGCP:        parseGCPRanges,
+	CloudAzure:      parseAzureRanges,
+	CloudCloudflare: parseCloudflareRanges,
+}
+
+func cloudNames() []string {
+	var names []string
+	for name := range cloudFeedParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cloudRange is a single prefix of a feed.
+type cloudRange struct {
+	cloud   string
+	prefix  netaddr.IPPrefix
+	service string
+	region  string
+}
+
+// matchCloudRange returns the most specific range, which contains the IP.
+// On equal prefix lengths, the range listed first wins.
+func matchCloudRange(ranges []cloudRange, ip netaddr.IP) (cloudRange, bool) {
+	var match cloudRange
+	found := false
+	for _, r := range ranges {
+		if r.prefix.Contains(ip) && (!found || r.prefix.Bits() > match.prefix.Bits()) {
+			match = r
+			found = true
+		}
+	}
+	return match, found
+}
+
+// fetchCloudRanges downloads and parses the feed of the cloud.
+func fetchCloudRanges(ctx context.Context, p *ProviderModel, client *http.Client, cloud string, feedURL string) ([]cloudRange, error) {
+	log.Printf("fetching the IP ranges 🔍: %s: %s", cloud, feedURL)
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", p.nextUserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the feed responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	body, err := readLimited(resp.Body, p.maxResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := cloudFeedParsers[cloud](body)
+	if err != nil {
+		return nil, fmt.Errorf("the feed could not be parsed: %w", err)
+	}
+	for i := range ranges {
+		ranges[i].cloud = cloud
+	}
+
+	log.Printf("got IP ranges ✅: %s: %d", cloud, len(ranges))
+	return ranges, nil
+}
+
+func parseAWSRanges(body []byte) ([]cloudRange, error) {
+	var feed struct {
+		Prefixes []struct {
+			IPPrefix string `json:"ip_prefix"`
+			Region   string `json:"region"`
+			Service  string `json:"service"`
+		} `json:"prefixes"`
+		IPv6Prefixes []struct {
+			IPv6Prefix string `json:"ipv6_prefix"`
+			Region     string `json:"region"`
+			Service    string `json:"service"`
+		} `json:"ipv6_prefixes"`
+	}
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every prefix is also listed with the generic service `AMAZON`, so the specific services are listed first.
+	var specific, generic []cloudRange
+	add := func(prefix string, service string, region string) error {
+		parsed, err := netaddr.ParseIPPrefix(prefix)
+		if err != nil {
+			return err
+		}
+		r := cloudRange{prefix: parsed, service: service, region: region}
+		if service == "AMAZON" {
+			generic = append(generic, r)
+		} else {
+			specific = append(specific, r)
+		}
+		return nil
+	}
+	for _, prefix := range feed.Prefixes {
+		if err := add(prefix.IPPrefix, prefix.Service, prefix.Region); err != nil {
+			return nil, err
+		}
+	}
+	for _, prefix := range feed.IPv6Prefixes {
+		if err := add(prefix.IPv6Prefix, prefix.Service, prefix.Region); err != nil {
+			return nil, err
+		}
+	}
+	return append(specific, generic...), nil
+}
+
+func parseGCPRanges(body []byte) ([]cloudRange, error) {
+	var feed struct {
+		Prefixes []struct {
+			IPv4Prefix string `json:"ipv4Prefix"`
+			IPv6Prefix string `json:"ipv6Prefix"`
+			Service    string `json:"service"`
+			Scope      string `json:"scope"`
+		} `json:"prefixes"`
+	}
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []cloudRange
+	for _, prefix := range feed.Prefixes {
+		parsed, err := netaddr.ParseIPPrefix(prefix.IPv4Prefix + prefix.IPv6Prefix)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, cloudRange{prefix: parsed, service: prefix.Service, region: prefix.Scope})
+	}
+	return ranges, nil
+}
+
+func parseAzureRanges(body []byte) ([]cloudRange, error) {
+	var feed struct {
+		Values []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				Region          string   `json:"region"`
+				AddressPrefixes []string `json:"addressPrefixes"`
+			} `json:"properties"`
+		} `json:"values"`
+	}
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
+	}
+
+	// The regional service tags, e.g. `AzureCloud.eastus`, are listed before the global ones, e.g. `AzureCloud`.
+	var regional, global []cloudRange
+	for _, value := range feed.Values {
+		for _, prefix := range value.Properties.AddressPrefixes {
+			parsed, err := netaddr.ParseIPPrefix(prefix)
+			if err != nil {
+				return nil, err
+			}
+			r := cloudRange{prefix: parsed, service: value.Name, region: value.Properties.Region}
+			if strings.Contains(value.Name, ".") {
+				regional = append(regional, r)
+			} else {
+				global = append(global, r)
+			}
+		}
+	}
+	return append(regional, global...), nil
+}
+
+func parseCloudflareRanges(body []byte) ([]cloudRange, error) {
+	var feed struct {
+		Success bool `json:"success"`
+		Result  struct {
+			IPv4CIDRs []string `json:"ipv4_cidrs"`
+			IPv6CIDRs []string `json:"ipv6_cidrs"`
+		} `json:"result"`
+	}
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
+	}
+	if !feed.Success {
+		return nil, fmt.Errorf("the Cloudflare API reported a failure")
+	}
+
+	var ranges []cloudRange
+	for _, prefix := range append(feed.Result.IPv4CIDRs, feed.Result.IPv6CIDRs...) {
+		parsed, err := netaddr.ParseIPPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, cloudRange{prefix: parsed})
+	}
+	return ranges, nil
+}
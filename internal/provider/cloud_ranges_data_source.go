@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type CloudRangesDataSource struct {
+	provider *ProviderModel
+}
+
+func NewCloudRangesDataSource() datasource.DataSource {
+	return &CloudRangesDataSource{}
+}
+
+func (d CloudRangesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cloud_ranges"
+}
+
+func (d CloudRangesDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Looks up whether the current public IP or an arbitrary IP belongs to a cloud, according to the IP range feeds published by the clouds. Useful to detect whether Terraform runs behind the NAT of a cloud.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"clouds": {
+				MarkdownDescription: fmt.Sprintf("The clouds, whose feeds are fetched. Expected values: '%s'. Defaults to all clouds, which have a feed URL, i.e. `%s` only if its feed is set in `feed_urls`.", strings.Join(cloudNames(), "', '"), CloudAzure),
+				Optional:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"feed_urls": {
+				MarkdownDescription: fmt.Sprintf("The URLs of the feeds by cloud, e.g. `{ %s = \"https://download.microsoft.com/download/7/1/D/71D86715-5596-4529-9B13-DA13A5DE5B63/ServiceTags_Public_20240101.json\" }`. The feed of `%s` has no stable URL, so it must be set to look it up. The other clouds default to their published feeds.", CloudAzure, CloudAzure),
+				Optional:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"cloud": {
+				MarkdownDescription: "The cloud, which the IP belongs to. `null` if it belongs to none of the `clouds`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"prefix": {
+				MarkdownDescription: "The most specific prefix of the feed, which contains the IP. `null` if the IP belongs to none of the `clouds`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"service": {
+				MarkdownDescription: "The service or service tag of the prefix, e.g. `EC2` or `AzureCloud.westeurope`. `null` if the feed does not state one.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region": {
+				MarkdownDescription: "The region of the prefix, e.g. `eu-central-1`. `null` if the feed does not state one.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *CloudRangesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type CloudRangesDataSourceModel struct {
+	ID       types.String      `tfsdk:"id"`
+	IP       types.String      `tfsdk:"ip"`
+	Clouds   []string          `tfsdk:"clouds"`
+	FeedURLs map[string]string `tfsdk:"feed_urls"`
+	Cloud    types.String      `tfsdk:"cloud"`
+	Prefix   types.String      `tfsdk:"prefix"`
+	Service  types.String      `tfsdk:"service"`
+	Region   types.String      `tfsdk:"region"`
+}
+
+func (d CloudRangesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data CloudRangesDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	feedURLs := map[string]string{}
+	for cloud, feedURL := range DefaultCloudFeedURLs {
+		feedURLs[cloud] = feedURL
+	}
+	for cloud, feedURL := range data.FeedURLs {
+		if _, ok := cloudFeedParsers[cloud]; !ok {
+			resp.Diagnostics.AddError("Invalid feed_urls", fmt.Sprintf("The cloud '%s' must be one of '%s'.", cloud, strings.Join(cloudNames(), "', '")))
+			return
+		}
+		feedURLs[cloud] = feedURL
+	}
+
+	clouds := data.Clouds
+	if clouds == nil {
+		for _, cloud := range cloudNames() {
+			if _, ok := feedURLs[cloud]; ok {
+				clouds = append(clouds, cloud)
+			}
+		}
+	}
+	for _, cloud := range clouds {
+		if _, ok := cloudFeedParsers[cloud]; !ok {
+			resp.Diagnostics.AddError("Invalid clouds", fmt.Sprintf("The cloud '%s' must be one of '%s'.", cloud, strings.Join(cloudNames(), "', '")))
+			return
+		}
+		if _, ok := feedURLs[cloud]; !ok {
+			resp.Diagnostics.AddError("Missing feed URL", fmt.Sprintf("The cloud '%s' has no default feed, set its URL in feed_urls.", cloud))
+			return
+		}
+	}
+
+	ip, _, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ip = ip.Unmap()
+
+	feeds := make([][]cloudRange, len(clouds))
+	errs := make([]error, len(clouds))
+	lookupClient := newLookupHTTPClient(d.provider)
+	var wg sync.WaitGroup
+	for i, cloud := range clouds {
+		wg.Add(1)
+		go func(i int, cloud string) {
+			defer wg.Done()
+			feeds[i], errs[i] = fetchCloudRanges(ctx, d.provider, lookupClient, cloud, feedURLs[cloud])
+		}(i, cloud)
+	}
+	wg.Wait()
+
+	var ranges []cloudRange
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Cloud ranges error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error fetching the IP ranges", fmt.Sprintf("The IP ranges of '%s' could not be fetched from '%s': %s", clouds[i], feedURLs[clouds[i]], err))
+		}
+		ranges = append(ranges, feeds[i]...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.Cloud = types.String{Null: true}
+	data.Prefix = types.String{Null: true}
+	data.Service = types.String{Null: true}
+	data.Region = types.String{Null: true}
+	if match, ok := matchCloudRange(ranges, ip); ok {
+		data.Cloud = types.String{Value: match.cloud}
+		data.Prefix = types.String{Value: match.prefix.String()}
+		data.Service = optionalString(match.service)
+		data.Region = optionalString(match.region)
+	}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.String{Null: true}
+	}
+	return types.String{Value: value}
+}
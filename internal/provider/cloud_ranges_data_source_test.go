@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+func testCloudFeedServer(t *testing.T) string {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			_, _ = w.Write([]byte(`{"ip":"3.5.140.7"}`))
+		case "/aws":
+			_, _ = w.Write([]byte(`{"prefixes":[
+				{"ip_prefix":"3.5.140.0/22","region":"ap-northeast-2","service":"AMAZON"},
+				{"ip_prefix":"3.5.140.0/22","region":"ap-northeast-2","service":"S3"},
+				{"ip_prefix":"3.0.0.0/9","region":"GLOBAL","service":"AMAZON"}
+			],"ipv6_prefixes":[{"ipv6_prefix":"2600:1f00::/24","region":"GLOBAL","service":"AMAZON"}]}`))
+		case "/gcp":
+			_, _ = w.Write([]byte(`{"prefixes":[{"ipv4Prefix":"34.1.208.0/20","service":"Google Cloud","scope":"africa-south1"},{"ipv6Prefix":"2600:1900:8000::/44","service":"Google Cloud","scope":"us-east4"}]}`))
+		case "/azure":
+			_, _ = w.Write([]byte(`{"values":[
+				{"name":"AzureCloud","properties":{"region":"","addressPrefixes":["20.0.0.0/8"]}},
+				{"name":"AzureCloud.westeurope","properties":{"region":"westeurope","addressPrefixes":["20.0.0.0/8"]}}
+			]}`))
+		case "/cloudflare":
+			_, _ = w.Write([]byte(`{"success":true,"result":{"ipv4_cidrs":["104.16.0.0/13"],"ipv6_cidrs":["2606:4700::/32"]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return server.URL
+}
+
+func TestCloudRangesDataSource(t *testing.T) {
+	serverURL := testCloudFeedServer(t)
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, serverURL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	feedURLs := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		CloudAWS:        tftypes.NewValue(tftypes.String, serverURL+"/aws"),
+		CloudGCP:        tftypes.NewValue(tftypes.String, serverURL+"/gcp"),
+		CloudAzure:      tftypes.NewValue(tftypes.String, serverURL+"/azure"),
+		CloudCloudflare: tftypes.NewValue(tftypes.String, serverURL+"/cloudflare"),
+	})
+
+	tests := []struct {
+		ip       string
+		expected []string
+	}{
+		{"", []string{"3.5.140.7", CloudAWS, "3.5.140.0/22", "S3", "ap-northeast-2"}},
+		{"3.6.0.1", []string{"3.6.0.1", CloudAWS, "3.0.0.0/9", "AMAZON", "GLOBAL"}},
+		{"2600:1900:8001::1", []string{"2600:1900:8001::1", CloudGCP, "2600:1900:8000::/44", "Google Cloud", "us-east4"}},
+		{"20.1.2.3", []string{"20.1.2.3", CloudAzure, "20.0.0.0/8", "AzureCloud.westeurope", "westeurope"}},
+		{"::ffff:104.16.1.1", []string{"104.16.1.1", CloudCloudflare, "104.16.0.0/13", "", ""}},
+		{"192.0.2.1", []string{"192.0.2.1", "", "", "", ""}},
+	}
+
+	for _, test := range tests {
+		attrs := map[string]tftypes.Value{"feed_urls": feedURLs}
+		if test.ip != "" {
+			attrs["ip"] = tftypes.NewValue(tftypes.String, test.ip)
+		}
+		resp := testReadDataSource(t, NewCloudRangesDataSource, providerData, attrs)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics for '%s': %v", test.ip, resp.Diagnostics)
+		}
+
+		var data CloudRangesDataSourceModel
+		resp.State.Get(context.Background(), &data)
+		actual := []string{data.IP.Value, data.Cloud.Value, data.Prefix.Value, data.Service.Value, data.Region.Value}
+		for i := range actual {
+			if actual[i] != test.expected[i] {
+				t.Errorf("expected %v for '%s', got %v", test.expected, test.ip, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestCloudRangesDataSourceAzureWithoutFeed(t *testing.T) {
+	resp := testReadDataSource(t, NewCloudRangesDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "20.1.2.3"),
+		"clouds": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, CloudAzure),
+		}),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for azure without feed URL")
+	}
+}
+
+func TestMatchCloudRange(t *testing.T) {
+	ranges := []cloudRange{
+		{cloud: CloudAWS, prefix: netaddr.MustParseIPPrefix("3.0.0.0/9")},
+		{cloud: CloudAWS, prefix: netaddr.MustParseIPPrefix("3.5.140.0/22"), service: "S3"},
+		{cloud: CloudAWS, prefix: netaddr.MustParseIPPrefix("3.5.140.0/22"), service: "AMAZON"},
+	}
+
+	match, ok := matchCloudRange(ranges, netaddr.MustParseIP("3.5.141.1"))
+	if !ok || match.service != "S3" {
+		t.Errorf("expected the most specific, first range, got %+v", match)
+	}
+	if _, ok := matchCloudRange(ranges, netaddr.MustParseIP("192.0.2.1")); ok {
+		t.Error("expected no match")
+	}
+}
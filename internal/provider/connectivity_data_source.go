@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ConnectivityDataSource struct {
+	provider *ProviderModel
+}
+
+func NewConnectivityDataSource() datasource.DataSource {
+	return &ConnectivityDataSource{}
+}
+
+func (d ConnectivityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_connectivity"
+}
+
+func (d ConnectivityDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Probes whether the IP information provider can be reached over IPv4 and over IPv6. The read never fails because of a missing IP stack, so modules can branch on the available stacks. See `publicip_addresses` for the details of each stack.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"has_ipv4": {
+				MarkdownDescription: "`true` if the IP information provider could be reached over IPv4.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"has_ipv6": {
+				MarkdownDescription: "`true` if the IP information provider could be reached over IPv6.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"ipv4": {
+				MarkdownDescription: "The public IPv4. `null` if `has_ipv4` is `false`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ipv6": {
+				MarkdownDescription: "The public IPv6. `null` if `has_ipv6` is `false`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *ConnectivityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type ConnectivityDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	HasIPv4 types.Bool   `tfsdk:"has_ipv4"`
+	HasIPv6 types.Bool   `tfsdk:"has_ipv6"`
+	IPv4    types.String `tfsdk:"ipv4"`
+	IPv6    types.String `tfsdk:"ipv6"`
+}
+
+func (d ConnectivityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data ConnectivityDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	log.Printf("probing the IPv4 and the IPv6 connectivity 🔍")
+
+	addresses := AddressesDataSource{provider: d.provider}
+	var ipv4, ipv6 *AddressFamilyModel
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ipv4 = addresses.lookupFamily(ctx, "tcp4")
+	}()
+	go func() {
+		defer wg.Done()
+		ipv6 = addresses.lookupFamily(ctx, "tcp6")
+	}()
+	wg.Wait()
+
+	data.HasIPv4 = ipv4.Available
+	data.HasIPv6 = ipv6.Available
+	data.IPv4 = ipv4.IP
+	data.IPv6 = ipv6.IP
+
+	var ids []string
+	for _, ip := range []types.String{data.IPv4, data.IPv6} {
+		if !ip.Null {
+			ids = append(ids, ip.Value)
+		}
+	}
+	data.ID = types.String{Value: strings.Join(ids, ",")}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestConnectivityDataSourceIPv4Only(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"203.0.113.4"}`)
+	}, "127.0.0.1")
+
+	resp := testReadDataSource(t, NewConnectivityDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data ConnectivityDataSourceModel
+	resp.State.Get(context.Background(), &data)
+
+	if !data.HasIPv4.Value || data.IPv4.Value != "203.0.113.4" {
+		t.Errorf("unexpected ipv4: %+v", data)
+	}
+	if data.HasIPv6.Value || !data.IPv6.Null {
+		t.Errorf("expected ipv6 to be unavailable: %+v", data)
+	}
+	if data.ID.Value != "203.0.113.4" {
+		t.Errorf("unexpected id '%s'", data.ID.Value)
+	}
+}
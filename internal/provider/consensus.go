@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// fetchIPByConsensus queries all consensus URLs concurrently and only accepts an IP,
+// which is returned by at least quorum of the IP information providers.
+func fetchIPByConsensus(ctx context.Context, p *ProviderModel, client *http.Client, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	type outcome struct {
+		providerURL *url.URL
+		result      *ipFetchResult
+		hint        retryHint
+		diags       diag.Diagnostics
+	}
+	outcomes := make(chan outcome, len(p.consensusURLs))
+	for _, providerURL := range p.consensusURLs {
+		go func(providerURL *url.URL) {
+			result, hint, diags := fetchIPWithRetries(ctx, p, client, providerURL, query)
+			outcomes <- outcome{providerURL, result, hint, diags}
+		}(providerURL)
+	}
+
+	votes := map[string][]*ipFetchResult{}
+	var answers []string
+	var hint retryHint
+	for range p.consensusURLs {
+		o := <-outcomes
+		if o.diags.HasError() {
+			hint = hint.join(o.hint)
+			var reasons []string
+			for _, e := range o.diags.Errors() {
+				reasons = append(reasons, e.Detail())
+			}
+			answers = append(answers, fmt.Sprintf("- %s: failed: %s", o.providerURL, strings.Join(reasons, " ")))
+			continue
+		}
+
+		ip := o.result.ip.Unmap().String()
+		votes[ip] = append(votes[ip], o.result)
+		answers = append(answers, fmt.Sprintf("- %s: %s", o.providerURL, ip))
+	}
+
+	// With a quorum of at most half of the providers, several IPs may reach it, which is a disagreement as well.
+	var agreed []string
+	for ip, results := range votes {
+		if len(results) >= p.consensusQuorum {
+			agreed = append(agreed, ip)
+		}
+	}
+	if len(agreed) == 1 {
+		results := votes[agreed[0]]
+		log.Printf("consensus reached 🤝: %s (%d of %d)", agreed[0], len(results), len(p.consensusURLs))
+		return results[0], retryHint{}, nil
+	}
+
+	sort.Strings(answers)
+	var diags diag.Diagnostics
+	if len(agreed) > 1 {
+		sort.Strings(agreed)
+		diags.AddError("No consensus on the IP", fmt.Sprintf("The IPs '%s' were each returned by at least %d of the %d IP information providers of the consensus:\n%s", strings.Join(agreed, "', '"), p.consensusQuorum, len(p.consensusURLs), strings.Join(answers, "\n")))
+		return nil, hint, diags
+	}
+	diags.AddError("No consensus on the IP", fmt.Sprintf("No IP was returned by at least %d of the %d IP information providers of the consensus:\n%s", p.consensusQuorum, len(p.consensusURLs), strings.Join(answers, "\n")))
+	return nil, hint, diags
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestConsensus(t *testing.T) {
+	var urls []tftypes.Value
+	for _, body := range []string{`{"ip":"203.0.113.4"}`, `{"ip":"203.0.113.4"}`, `{"ip":"198.51.100.6"}`} {
+		body := body
+		server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+		urls = append(urls, tftypes.NewValue(tftypes.String, server.URL))
+	}
+
+	consensusType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"urls":   tftypes.List{ElementType: tftypes.String},
+		"quorum": tftypes.Number,
+	}}
+	consensus := func(quorum interface{}) tftypes.Value {
+		return tftypes.NewValue(consensusType, map[string]tftypes.Value{
+			"urls":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, urls),
+			"quorum": tftypes.NewValue(tftypes.Number, quorum),
+		})
+	}
+
+	// The majority of two agrees.
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"consensus":        consensus(nil),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" {
+		t.Errorf("expected the IP of the majority, got '%s'", data.IP.Value)
+	}
+
+	// All three must agree.
+	providerData = testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"consensus":        consensus(3),
+	})
+	resp = testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error on disagreement")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "198.51.100.6") {
+		t.Errorf("expected the answers of all providers in the error, got: %s", detail)
+	}
+}
+
+func TestConsensusSplit(t *testing.T) {
+	var urls []tftypes.Value
+	for _, body := range []string{`{"ip":"203.0.113.4"}`, `{"ip":"203.0.113.4"}`, `{"ip":"198.51.100.6"}`, `{"ip":"198.51.100.6"}`} {
+		body := body
+		server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+		urls = append(urls, tftypes.NewValue(tftypes.String, server.URL))
+	}
+
+	consensusType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"urls":   tftypes.List{ElementType: tftypes.String},
+		"quorum": tftypes.Number,
+	}}
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"consensus": tftypes.NewValue(consensusType, map[string]tftypes.Value{
+			"urls":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, urls),
+			"quorum": tftypes.NewValue(tftypes.Number, 2),
+		}),
+	})
+
+	// Both IPs reach the quorum of 2 of 4.
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error on a split")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "'198.51.100.6', '203.0.113.4'") {
+		t.Errorf("expected both IPs in the error, got: %s", detail)
+	}
+}
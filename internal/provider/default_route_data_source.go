@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DefaultRouteDataSource struct {
+	provider *ProviderModel
+	// defaultRoutes reads the default route of an IP version from the routing table.
+	defaultRoutes map[string]func() (defaultRoute, error)
+}
+
+func NewDefaultRouteDataSource() datasource.DataSource {
+	return &DefaultRouteDataSource{defaultRoutes: map[string]func() (defaultRoute, error){
+		IPVersion4: defaultRouteIPv4,
+		IPVersion6: defaultRouteIPv6,
+	}}
+}
+
+func (d DefaultRouteDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_default_route"
+}
+
+func (d DefaultRouteDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The default route of IPv4 and of IPv6 and the local IP, which the operating system chooses to reach the IP information provider. Useful to debug, which source the other data sources use. The routing table can only be read on Linux, the local IP is determined on every system. No request is sent.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ipv4": {
+				MarkdownDescription: "The default route of IPv4.",
+				Computed:            true,
+				Attributes:          tfsdk.SingleNestedAttributes(defaultRouteAttributes()),
+			},
+			"ipv6": {
+				MarkdownDescription: "The default route of IPv6.",
+				Computed:            true,
+				Attributes:          tfsdk.SingleNestedAttributes(defaultRouteAttributes()),
+			},
+		},
+	}, nil
+}
+
+func defaultRouteAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"available": {
+			MarkdownDescription: "`true` if there is a route to the IP information provider over this IP stack.",
+			Computed:            true,
+			Type:                types.BoolType,
+		},
+		"error": {
+			MarkdownDescription: "Why the default route or the local IP could not be determined. `null` if both could be determined.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"interface": {
+			MarkdownDescription: "The name of the egress interface of the default route, e.g. `eth0`. `null` if the routing table could not be read.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"gateway": {
+			MarkdownDescription: "The gateway of the default route. `null` if the routing table could not be read or the route has no gateway, e.g. over a point-to-point link.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+		"local_ip": {
+			MarkdownDescription: "The local IP, which the operating system chooses to reach the IP information provider. `null` if it is not `available`.",
+			Computed:            true,
+			Type:                types.StringType,
+		},
+	}
+}
+
+func (d *DefaultRouteDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type DefaultRouteDataSourceModel struct {
+	ID   types.String       `tfsdk:"id"`
+	IPv4 *DefaultRouteModel `tfsdk:"ipv4"`
+	IPv6 *DefaultRouteModel `tfsdk:"ipv6"`
+}
+
+type DefaultRouteModel struct {
+	Available types.Bool   `tfsdk:"available"`
+	Error     types.String `tfsdk:"error"`
+	Interface types.String `tfsdk:"interface"`
+	Gateway   types.String `tfsdk:"gateway"`
+	LocalIP   types.String `tfsdk:"local_ip"`
+}
+
+func (d DefaultRouteDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data DefaultRouteDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.IPv4 = d.lookupRoute(ctx, IPVersion4, "udp4")
+	data.IPv6 = d.lookupRoute(ctx, IPVersion6, "udp6")
+
+	var ids []string
+	for _, route := range []*DefaultRouteModel{data.IPv4, data.IPv6} {
+		if route.Available.Value {
+			ids = append(ids, route.LocalIP.Value)
+		}
+	}
+	data.ID = types.String{Value: strings.Join(ids, ",")}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookupRoute reads the default route and the local IP of the IP version.
+// Errors are reported in the returned model rather than as diagnostics, as the IP stack may be missing.
+func (d DefaultRouteDataSource) lookupRoute(ctx context.Context, version string, network string) *DefaultRouteModel {
+	model := &DefaultRouteModel{
+		Available: types.Bool{Value: false},
+		Error:     types.String{Null: true},
+		Interface: types.String{Null: true},
+		Gateway:   types.String{Null: true},
+		LocalIP:   types.String{Null: true},
+	}
+	var reasons []string
+
+	route, err := d.defaultRoutes[version]()
+	if err != nil {
+		log.Printf("'%s' default route unavailable 🚨: %s", version, err)
+		reasons = append(reasons, fmt.Sprintf("The default route could not be read: %s", err))
+	} else {
+		model.Interface = types.String{Value: route.iface}
+		if !route.gateway.IsZero() {
+			model.Gateway = types.String{Value: route.gateway.String()}
+		}
+	}
+
+	ip, err := localIP(ctx, d.provider, network)
+	if err == nil && ipVersion(ip) != version {
+		err = fmt.Errorf("got the local IP '%s'", ip)
+	}
+	if err != nil {
+		log.Printf("'%s' local IP unavailable 🚨: %s", version, err)
+		reasons = append(reasons, fmt.Sprintf("The local IP could not be determined: %s", err))
+	} else {
+		model.Available = types.Bool{Value: true}
+		model.LocalIP = types.String{Value: ip.String()}
+	}
+
+	if len(reasons) > 0 {
+		model.Error = types.String{Value: strings.Join(reasons, "\n")}
+	}
+	return model
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func TestParseIPv4DefaultRoute(t *testing.T) {
+	table := `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+wlan0	00000000	FE01A8C0	0003	0	0	600	00000000	0	0	0
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+`
+	route, err := parseIPv4DefaultRoute(strings.NewReader(table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.iface != "eth0" || route.gateway.String() != "192.168.1.1" {
+		t.Errorf("unexpected default route: %+v", route)
+	}
+
+	_, err = parseIPv4DefaultRoute(strings.NewReader("Iface\tDestination\tGateway\n"))
+	if err == nil {
+		t.Error("expected an error without default route")
+	}
+}
+
+func TestParseIPv6DefaultRoute(t *testing.T) {
+	table := `fd000000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000400 00000002 00000000 00000003     eth0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000001 00000000 00000001     wg0
+00000000000000000000000000000000 00 00000000000000000000000000000000 00 00000000000000000000000000000000 ffffffff 00000001 00000000 00200200       lo
+`
+	route, err := parseIPv6DefaultRoute(strings.NewReader(table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.iface != "wg0" || !route.gateway.IsZero() {
+		t.Errorf("unexpected default route: %+v", route)
+	}
+
+	route, err = parseIPv6DefaultRoute(strings.NewReader(strings.SplitAfterN(table, "\n", 3)[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.iface != "eth0" || route.gateway.String() != "fe80::1" {
+		t.Errorf("unexpected default route: %+v", route)
+	}
+}
+
+func TestDefaultRouteDataSourceIPv4Only(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {}, "127.0.0.1")
+	newDataSource := func() datasource.DataSource {
+		return &DefaultRouteDataSource{defaultRoutes: map[string]func() (defaultRoute, error){
+			IPVersion4: func() (defaultRoute, error) {
+				return parseIPv4DefaultRoute(strings.NewReader("eth0\t00000000\t0101A8C0\t0003\t0\t0\t0\t00000000\n"))
+			},
+			IPVersion6: func() (defaultRoute, error) { return defaultRoute{}, fmt.Errorf("there is no default route") },
+		}}
+	}
+
+	resp := testReadDataSource(t, newDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data DefaultRouteDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if !data.IPv4.Available.Value || data.IPv4.LocalIP.Value != "127.0.0.1" || data.IPv4.Interface.Value != "eth0" || data.IPv4.Gateway.Value != "192.168.1.1" || !data.IPv4.Error.Null {
+		t.Errorf("unexpected ipv4: %+v", data.IPv4)
+	}
+	if data.IPv6.Available.Value || !data.IPv6.LocalIP.Null || !data.IPv6.Interface.Null || data.IPv6.Error.Value == "" {
+		t.Errorf("expected ipv6 to be unavailable: %+v", data.IPv6)
+	}
+}
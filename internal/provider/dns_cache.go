@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsCache caches the resolved addresses of the IP information provider's host for a short time,
+// so that many data sources don't resolve the same host over and over again.
+type dnsCache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		lookup:  net.DefaultResolver.LookupIPAddr,
+		entries: map[string]dnsCacheEntry{},
+	}
+}
+
+// resolve returns the cached addresses of host and resolves them if they are missing or expired.
+func (c *dnsCache) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[host]
+	if ok && time.Now().Before(entry.expires) {
+		log.Printf("DNS cache hit 🗃️: '%s'", host)
+		return entry.addrs, nil
+	}
+
+	log.Printf("DNS cache miss 🔍: '%s'", host)
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	return addrs, nil
+}
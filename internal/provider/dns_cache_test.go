@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDNSCacheResolvesOnce(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+	serverURL, _ := url.Parse(server.URL)
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, "http://ip.example.test:"+serverURL.Port()),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"dns_cache_ttl":    tftypes.NewValue(tftypes.String, "1m"),
+	})
+
+	var lookups int32
+	providerData.dnsCache.lookup = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		atomic.AddInt32(&lookups, 1)
+		if host != "ip.example.test" {
+			t.Errorf("unexpected lookup of '%s'", host)
+		}
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+	}
+
+	if lookups != 1 {
+		t.Errorf("expected 1 DNS lookup, got %d", lookups)
+	}
+}
+
+func TestDNSCacheExpires(t *testing.T) {
+	cache := newDNSCache(time.Millisecond)
+
+	var lookups int
+	cache.lookup = func(_ context.Context, _ string) ([]net.IPAddr, error) {
+		lookups++
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	_, _ = cache.resolve(context.Background(), "ip.example.test")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = cache.resolve(context.Background(), "ip.example.test")
+
+	if lookups != 2 {
+		t.Errorf("expected 2 DNS lookups, got %d", lookups)
+	}
+}
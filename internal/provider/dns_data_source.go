@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/net/dns/dnsmessage"
+	"inet.af/netaddr"
+)
+
+// dnsMethod is a special DNS name, which resolves to the IP of the client on its authoritative server.
+type dnsMethod struct {
+	resolver string
+	name     string
+	// qtype is the record type to query, A or AAAA are chosen by the IP version if it's zero.
+	qtype dnsmessage.Type
+	class dnsmessage.Class
+}
+
+const DefaultDNSMethod = "opendns"
+
+var dnsMethods = map[string]dnsMethod{
+	"opendns":    {resolver: "resolver1.opendns.com:53", name: "myip.opendns.com.", class: dnsmessage.ClassINET},
+	"google":     {resolver: "ns1.google.com:53", name: "o-o.myaddr.l.google.com.", qtype: dnsmessage.TypeTXT, class: dnsmessage.ClassINET},
+	"cloudflare": {resolver: "one.one.one.one:53", name: "whoami.cloudflare.", qtype: dnsmessage.TypeTXT, class: dnsmessage.ClassCHAOS},
+}
+
+func dnsMethodNames() []string {
+	var names []string
+	for name := range dnsMethods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type DNSDataSource struct {
+	provider *ProviderModel
+}
+
+func NewDNSDataSource() datasource.DataSource {
+	return &DNSDataSource{}
+}
+
+func (d DNSDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns"
+}
+
+func (d DNSDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The current public IP as seen by an authoritative DNS server, which echoes the IP of the client. Useful in networks, where HTTP requests to the IP information provider are blocked, but DNS works.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"method": {
+				MarkdownDescription: fmt.Sprintf("How the IP is queried. `opendns` resolves `myip.opendns.com` with `resolver1.opendns.com`, `google` resolves the TXT of `o-o.myaddr.l.google.com` with `ns1.google.com` and `cloudflare` resolves the CHAOS TXT of `whoami.cloudflare` with `one.one.one.one`. Expected values: '%s'. Defaults to `%s`.", strings.Join(dnsMethodNames(), "', '"), DefaultDNSMethod),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"resolver": {
+				MarkdownDescription: "The DNS server with its port, which is queried. Defaults to the server of the method.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the returned IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'. Set it to '%s' or '%s' to request the IP of that version.", IPVersion6, IPVersion4, IPUnknown, IPVersion6, IPVersion4),
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP as seen by the DNS server.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *DNSDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type DNSDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Method    types.String `tfsdk:"method"`
+	Resolver  types.String `tfsdk:"resolver"`
+	IPVersion types.String `tfsdk:"ip_version"`
+	IP        types.String `tfsdk:"ip"`
+}
+
+func (d DNSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data DNSDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	methodName := DefaultDNSMethod
+	if !data.Method.Null {
+		methodName = data.Method.Value
+	}
+	method, ok := dnsMethods[methodName]
+	if !ok {
+		resp.Diagnostics.AddError("Invalid method", fmt.Sprintf("The method '%s' must be one of '%s'.", methodName, strings.Join(dnsMethodNames(), "', '")))
+		return
+	}
+
+	network, ok := stunNetwork(data.IPVersion, resp)
+	if !ok {
+		return
+	}
+
+	resolver := method.resolver
+	if !data.Resolver.Null && !data.Resolver.Unknown {
+		resolver = data.Resolver.Value
+	}
+
+	resolverAddr, err := net.ResolveUDPAddr(network, resolver)
+	if err != nil {
+		log.Printf("DNS error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error resolving the DNS server", fmt.Sprintf("The DNS server '%s' could not be resolved: %s", resolver, err))
+		return
+	}
+
+	qtype := method.qtype
+	if qtype == 0 {
+		qtype = dnsmessage.TypeA
+		if resolverAddr.IP.To4() == nil {
+			qtype = dnsmessage.TypeAAAA
+		}
+	}
+
+	answers, err := dnsQuery(ctx, network, resolverAddr.String(), method.name, qtype, method.class, d.provider.timeout)
+	if err != nil {
+		log.Printf("DNS error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error querying the DNS server", fmt.Sprintf("The DNS server '%s' could not be queried for '%s': %s", resolver, method.name, err))
+		return
+	}
+
+	ip, ok := dnsAnswerIP(answers)
+	if !ok {
+		resp.Diagnostics.AddError("No IP in the DNS response", fmt.Sprintf("The DNS server '%s' did not return an IP for '%s'.", resolver, method.name))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.Method = types.String{Value: methodName}
+	data.Resolver = types.String{Value: resolver}
+	data.IP = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// dnsAnswerIP returns the first IP in the answers, either as A, AAAA or TXT record.
+// Other TXT records, e.g. the `edns0-client-subnet` of Google, are ignored.
+func dnsAnswerIP(answers []dnsmessage.Resource) (netaddr.IP, bool) {
+	for _, answer := range answers {
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			return netaddr.IPFrom4(body.A), true
+		case *dnsmessage.AAAAResource:
+			return netaddr.IPFrom16(body.AAAA), true
+		case *dnsmessage.TXTResource:
+			for _, txt := range body.TXT {
+				if ip, err := netaddr.ParseIP(strings.TrimSpace(txt)); err == nil {
+					return ip, true
+				}
+			}
+		}
+	}
+	return netaddr.IP{}, false
+}
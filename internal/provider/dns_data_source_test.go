@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testDNSServer starts a mock DNS server, which answers the questions with the answer bodies.
+func testDNSServer(t *testing.T, answers map[dnsmessage.Question][]dnsmessage.ResourceBody) string {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1232)
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+
+			var query dnsmessage.Message
+			if query.Unpack(buffer[:n]) != nil || len(query.Questions) != 1 {
+				continue
+			}
+			question := query.Questions[0]
+
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RCode: dnsmessage.RCodeNameError},
+				Questions: query.Questions,
+			}
+			if bodies, ok := answers[question]; ok {
+				response.RCode = dnsmessage.RCodeSuccess
+				for _, body := range bodies {
+					response.Answers = append(response.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: question.Class},
+						Body:   body,
+					})
+				}
+			}
+
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteToUDP(packed, from)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNSDataSource(t *testing.T) {
+	resolver := testDNSServer(t, map[dnsmessage.Question][]dnsmessage.ResourceBody{
+		{Name: dnsmessage.MustNewName("myip.opendns.com."), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}: {
+			&dnsmessage.AResource{A: [4]byte{203, 0, 113, 4}},
+		},
+		{Name: dnsmessage.MustNewName("o-o.myaddr.l.google.com."), Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET}: {
+			&dnsmessage.TXTResource{TXT: []string{"edns0-client-subnet 198.51.100.0/24"}},
+			&dnsmessage.TXTResource{TXT: []string{"203.0.113.5"}},
+		},
+		{Name: dnsmessage.MustNewName("whoami.cloudflare."), Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassCHAOS}: {
+			&dnsmessage.TXTResource{TXT: []string{"203.0.113.6"}},
+		},
+	})
+
+	tests := map[string]string{
+		"opendns":    "203.0.113.4",
+		"google":     "203.0.113.5",
+		"cloudflare": "203.0.113.6",
+	}
+	for method, expected := range tests {
+		t.Run(method, func(t *testing.T) {
+			providerData := testProviderData(t, nil)
+			resp := testReadDataSource(t, NewDNSDataSource, providerData, map[string]tftypes.Value{
+				"method":   tftypes.NewValue(tftypes.String, method),
+				"resolver": tftypes.NewValue(tftypes.String, resolver),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data DNSDataSourceModel
+			resp.State.Get(context.Background(), &data)
+			if data.IP.Value != expected || data.IPVersion.Value != IPVersion4 || data.Resolver.Value != resolver {
+				t.Errorf("expected the IP '%s', got: %+v", expected, data)
+			}
+		})
+	}
+}
+
+func TestDNSDataSourceInvalidMethod(t *testing.T) {
+	resp := testReadDataSource(t, NewDNSDataSource, nil, map[string]tftypes.Value{
+		"method": tftypes.NewValue(tftypes.String, "akamai"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid method")
+	}
+}
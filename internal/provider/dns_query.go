@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"inet.af/netaddr"
+)
+
+// dnsQuery sends a single question directly to the DNS server and returns the answers.
+// Unlike the resolver of the system, it supports any class, e.g. CHAOS.
+func dnsQuery(ctx context.Context, network string, server string, name string, qtype dnsmessage.Type, class dnsmessage.Class, timeout time.Duration) ([]dnsmessage.Resource, error) {
+	questionName, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var id [2]byte
+	_, err = rand.Read(id[:])
+	if err != nil {
+		return nil, err
+	}
+
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: binary.BigEndian.Uint16(id[:]), RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: questionName, Type: qtype, Class: class}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	log.Printf("querying DNS 🔍: %s %s %s @%s", name, class, qtype, server)
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	_, err = conn.Write(packed)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buffer)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("the DNS server did not respond in time")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var response dnsmessage.Message
+		err = response.Unpack(buffer[:n])
+		if err != nil || response.ID != query.ID || !response.Response {
+			log.Printf("ignoring DNS message 🗑️: %x", buffer[:n])
+			continue
+		}
+		if response.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("the DNS server responded with %s", response.RCode)
+		}
+		return response.Answers, nil
+	}
+}
+
+// reverseDNSLabels returns the labels of the IP in the reverse order used by DNS zones like `in-addr.arpa`,
+// i.e. the octets of IPv4 and the nibbles of IPv6, without a zone.
+func reverseDNSLabels(ip netaddr.IP) string {
+	var labels []string
+	if ip.Is4() || ip.Is4in6() {
+		octets := ip.Unmap().As4()
+		for i := len(octets) - 1; i >= 0; i-- {
+			labels = append(labels, fmt.Sprintf("%d", octets[i]))
+		}
+		return strings.Join(labels, ".")
+	}
+
+	bytes := ip.As16()
+	for i := len(bytes) - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%x.%x", bytes[i]&0x0f, bytes[i]>>4))
+	}
+	return strings.Join(labels, ".")
+}
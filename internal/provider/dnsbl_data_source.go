@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const DefaultDNSBL = "zen.spamhaus.org"
+
+// dnsblErrorPrefix is used by Spamhaus and others to signal an error instead of a listing, e.g. `127.255.255.254` for queries through public resolvers.
+var dnsblErrorPrefix = netaddr.MustParseIPPrefix("127.255.255.0/24")
+
+type DNSBLDataSource struct {
+	provider *ProviderModel
+	// lookupHost resolves the A records of the DNSBL names.
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	// lookupTXT resolves the reason of a listing.
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+func NewDNSBLDataSource() datasource.DataSource {
+	return &DNSBLDataSource{lookupHost: net.DefaultResolver.LookupHost, lookupTXT: net.DefaultResolver.LookupTXT}
+}
+
+func (d DNSBLDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dnsbl"
+}
+
+func (d DNSBLDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Checks whether the current public IP or an arbitrary IP is listed on DNS-based blocklists (DNSBL) using the local resolver. Note that some DNSBLs, e.g. Spamhaus, refuse queries through public resolvers.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to check. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"lists": {
+				MarkdownDescription: fmt.Sprintf("The zones of the DNSBLs to check, e.g. `bl.spamcop.net`. Defaults to `[\"%s\"]`.", DefaultDNSBL),
+				Optional:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"listed": {
+				MarkdownDescription: "`true` if the IP is listed on any of the DNSBLs.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"results": {
+				MarkdownDescription: "The result of each DNSBL, in the order of `lists`.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"list": {
+						MarkdownDescription: "The zone of the DNSBL.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"listed": {
+						MarkdownDescription: "`true` if the IP is listed on the DNSBL.",
+						Computed:            true,
+						Type:                types.BoolType,
+					},
+					"return_codes": {
+						MarkdownDescription: "The addresses returned by the DNSBL, which encode the reason of the listing, e.g. `127.0.0.2`. Empty if the IP is not listed.",
+						Computed:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"reason": {
+						MarkdownDescription: "The TXT record of the listing. `null` if the IP is not listed or the DNSBL provides no TXT record.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *DNSBLDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type DNSBLDataSourceModel struct {
+	ID      types.String       `tfsdk:"id"`
+	IP      types.String       `tfsdk:"ip"`
+	Lists   []string           `tfsdk:"lists"`
+	Listed  types.Bool         `tfsdk:"listed"`
+	Results []DNSBLResultModel `tfsdk:"results"`
+}
+
+type DNSBLResultModel struct {
+	List        types.String `tfsdk:"list"`
+	Listed      types.Bool   `tfsdk:"listed"`
+	ReturnCodes []string     `tfsdk:"return_codes"`
+	Reason      types.String `tfsdk:"reason"`
+}
+
+func (d DNSBLDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data DNSBLDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, _, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lists := data.Lists
+	if lists == nil {
+		lists = []string{DefaultDNSBL}
+	}
+
+	results := make([]DNSBLResultModel, len(lists))
+	errs := make([]error, len(lists))
+	var wg sync.WaitGroup
+	for i, list := range lists {
+		wg.Add(1)
+		go func(i int, list string) {
+			defer wg.Done()
+			results[i], errs[i] = d.checkList(ctx, ip, strings.Trim(list, "."))
+		}(i, list)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("DNSBL error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error checking the DNSBL", fmt.Sprintf("The IP '%s' could not be checked against '%s': %s", ip, lists[i], err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.Listed = types.Bool{Value: false}
+	for _, result := range results {
+		if result.Listed.Value {
+			data.Listed = types.Bool{Value: true}
+		}
+	}
+	data.Results = results
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// checkList looks up the IP in the DNSBL zone. A missing record means, that the IP is not listed.
+func (d DNSBLDataSource) checkList(ctx context.Context, ip netaddr.IP, list string) (DNSBLResultModel, error) {
+	name := reverseDNSLabels(ip) + "." + list
+	result := DNSBLResultModel{
+		List:        types.String{Value: list},
+		Listed:      types.Bool{Value: false},
+		ReturnCodes: []string{},
+		Reason:      types.String{Null: true},
+	}
+
+	log.Printf("checking the DNSBL 🔍: %s", name)
+
+	addresses, err := d.lookupHost(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	for _, address := range addresses {
+		code, err := netaddr.ParseIP(address)
+		if err != nil {
+			return result, fmt.Errorf("the DNSBL returned the invalid address '%s'", address)
+		}
+		if dnsblErrorPrefix.Contains(code) {
+			return result, fmt.Errorf("the DNSBL refused the query with '%s', it may not accept queries through the configured resolver", code)
+		}
+		result.ReturnCodes = append(result.ReturnCodes, code.String())
+	}
+	result.Listed = types.Bool{Value: len(result.ReturnCodes) > 0}
+
+	if result.Listed.Value {
+		reasons, err := d.lookupTXT(ctx, name)
+		if err == nil && len(reasons) > 0 {
+			result.Reason = types.String{Value: strings.Join(reasons, "\n")}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testDNSBLDataSource(records map[string][]string, reasons map[string][]string) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &DNSBLDataSource{
+			lookupHost: func(_ context.Context, host string) ([]string, error) {
+				if addresses, ok := records[host]; ok {
+					return addresses, nil
+				}
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			},
+			lookupTXT: func(_ context.Context, name string) ([]string, error) {
+				if reasons, ok := reasons[name]; ok {
+					return reasons, nil
+				}
+				return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+			},
+		}
+	}
+}
+
+func TestDNSBLDataSource(t *testing.T) {
+	newDataSource := testDNSBLDataSource(map[string][]string{
+		"4.113.0.203.zen.spamhaus.org": {"127.0.0.2", "127.0.0.10"},
+	}, map[string][]string{
+		"4.113.0.203.zen.spamhaus.org": {"Listed by SBL"},
+	})
+
+	resp := testReadDataSource(t, newDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "203.0.113.4"),
+		"lists": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "zen.spamhaus.org"),
+			tftypes.NewValue(tftypes.String, "bl.spamcop.net."),
+		}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data DNSBLDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if !data.Listed.Value || len(data.Results) != 2 {
+		t.Fatalf("expected the IP to be listed: %+v", data)
+	}
+
+	zen := data.Results[0]
+	if zen.List.Value != "zen.spamhaus.org" || !zen.Listed.Value || len(zen.ReturnCodes) != 2 || zen.ReturnCodes[1] != "127.0.0.10" || zen.Reason.Value != "Listed by SBL" {
+		t.Errorf("unexpected result: %+v", zen)
+	}
+	spamcop := data.Results[1]
+	if spamcop.List.Value != "bl.spamcop.net" || spamcop.Listed.Value || len(spamcop.ReturnCodes) != 0 || !spamcop.Reason.Null {
+		t.Errorf("unexpected result: %+v", spamcop)
+	}
+}
+
+func TestDNSBLDataSourceRefused(t *testing.T) {
+	newDataSource := testDNSBLDataSource(map[string][]string{
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.zen.spamhaus.org": {"127.255.255.254"},
+	}, nil)
+
+	resp := testReadDataSource(t, newDataSource, nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "2001:db8::1"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a refused query")
+	}
+}
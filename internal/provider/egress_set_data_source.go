@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const DefaultEgressSetSamples = 5
+const DefaultEgressSetInterval = "1s"
+
+type EgressSetDataSource struct {
+	provider *ProviderModel
+}
+
+func NewEgressSetDataSource() datasource.DataSource {
+	return &EgressSetDataSource{}
+}
+
+func (d EgressSetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_egress_set"
+}
+
+func (d EgressSetDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Samples the current public IP several times, each over a new connection, to detect NATs, which balance the connections over multiple public IPs. Note that the rate limit of the provider applies to the samples.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"samples": {
+				MarkdownDescription: fmt.Sprintf("How many times the public IP is requested. Defaults to `%d`.", DefaultEgressSetSamples),
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"interval": {
+				MarkdownDescription: fmt.Sprintf("How long to wait between the samples. Defaults to `%s`.", DefaultEgressSetInterval),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"ips": {
+				MarkdownDescription: "The distinct public IPs, in the order they were first observed.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"is_stable": {
+				MarkdownDescription: "`true` if all samples observed the same public IP.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+		},
+	}, nil
+}
+
+func (d *EgressSetDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type EgressSetDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Samples  types.Int64  `tfsdk:"samples"`
+	Interval types.String `tfsdk:"interval"`
+	IPs      []string     `tfsdk:"ips"`
+	IsStable types.Bool   `tfsdk:"is_stable"`
+}
+
+func (d EgressSetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data EgressSetDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	samples := int64(DefaultEgressSetSamples)
+	if !data.Samples.Null {
+		samples = data.Samples.Value
+	}
+	if samples < 1 {
+		resp.Diagnostics.AddError("Invalid samples", fmt.Sprintf("The samples value '%d' must be at least 1.", samples))
+		return
+	}
+
+	intervalStr := DefaultEgressSetInterval
+	if !data.Interval.Null {
+		intervalStr = data.Interval.Value
+	}
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to parse the interval", fmt.Sprintf("The interval value '%s' can't be parsed: %s", intervalStr, err))
+		return
+	}
+
+	log.Printf("sampling the public IP 🔍: %d times every %s", samples, interval)
+
+	seen := map[netaddr.IP]bool{}
+	data.IPs = []string{}
+	for i := int64(0); i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				resp.Diagnostics.AddError("Sampling cancelled", fmt.Sprintf("The sampling was cancelled after %d samples: %s", i, ctx.Err()))
+				return
+			case <-time.After(interval):
+			}
+		}
+
+		// A new client opens a new connection, which the NAT may map to another public IP.
+		client := newHTTPClient(d.provider, "tcp", netaddr.IP{})
+		result, diags := fetchIPFromProviders(ctx, d.provider, client, nil)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !seen[result.ip] {
+			seen[result.ip] = true
+			data.IPs = append(data.IPs, result.ip.String())
+		}
+	}
+
+	data.ID = types.String{Value: strings.Join(data.IPs, ",")}
+	data.IsStable = types.Bool{Value: len(data.IPs) == 1}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
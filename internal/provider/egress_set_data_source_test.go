@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEgressSetDataSource(t *testing.T) {
+	// The NAT balances the connections over two public IPs.
+	var requests int64
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&requests, 1)
+		_, _ = fmt.Fprintf(w, `{"ip":"203.0.113.%d"}`, 1+n%2)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	resp := testReadDataSource(t, NewEgressSetDataSource, providerData, map[string]tftypes.Value{
+		"samples":  tftypes.NewValue(tftypes.Number, 3),
+		"interval": tftypes.NewValue(tftypes.String, "1ms"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data EgressSetDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if requests != 3 || len(data.IPs) != 2 || data.IPs[0] != "203.0.113.2" || data.IPs[1] != "203.0.113.1" || data.IsStable.Value {
+		t.Errorf("unexpected egress set after %d requests: %+v", requests, data)
+	}
+}
+
+func TestEgressSetDataSourceStable(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	resp := testReadDataSource(t, NewEgressSetDataSource, providerData, map[string]tftypes.Value{
+		"samples":  tftypes.NewValue(tftypes.Number, 2),
+		"interval": tftypes.NewValue(tftypes.String, "1ms"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data EgressSetDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if len(data.IPs) != 1 || !data.IsStable.Value {
+		t.Errorf("expected a stable egress: %+v", data)
+	}
+}
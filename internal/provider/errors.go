@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// errorClass groups the failures, which users can resolve the same way.
+type errorClass string
+
+const (
+	errorClassUnknown         errorClass = "unknown"
+	errorClassTimeout         errorClass = "timeout"
+	errorClassRateLimited     errorClass = "rate_limited"
+	errorClassDNS             errorClass = "dns"
+	errorClassNoRoute         errorClass = "no_route"
+	errorClassTLS             errorClass = "tls"
+	errorClassParse           errorClass = "parse"
+	errorClassInvalidSourceIP errorClass = "invalid_source_ip"
+)
+
+const docsURL = "https://registry.terraform.io/providers/nxt-engineering/publicip/latest/docs"
+
+// remediations tell the users what to try for each errorClass.
+var remediations = map[errorClass]string{
+	errorClassTimeout:         "The IP information provider did not respond in time. Increase `timeout`, `request_timeout` or `total_timeout` in the provider configuration, or check whether a firewall silently drops the connection. See " + docsURL,
+	errorClassRateLimited:     "The IP information provider rejected the request because of too many requests. Lower `rate_limit_rate` and `rate_limit_burst` in the provider configuration, reduce the number of data sources or use your own IP information provider via `provider_url`. See " + docsURL,
+	errorClassDNS:             "The host of the IP information provider could not be resolved. Check the `provider_url` and the DNS configuration of this machine.",
+	errorClassNoRoute:         "There is no network route to the IP information provider. Check the network connection and, if set, whether the `source_ip` belongs to a connected interface. An IPv6 `source_ip` requires working IPv6 connectivity.",
+	errorClassTLS:             "The TLS connection to the IP information provider could not be established. Check whether the `provider_url` is correct and whether a proxy intercepts the connection. If `pinned_spki_hashes` is set, check whether the IP information provider changed its key.",
+	errorClassParse:           "The response of the IP information provider could not be understood. Check that the `provider_url` points to an ifconfig.co-compatible service and not e.g. to a captive portal. See https://github.com/mpolden/echoip",
+	errorClassInvalidSourceIP: "The `source_ip` must be an IP, e.g. `0.0.0.0` or `::`, or a hostname resolving to an IP, which is configured on a local network interface.",
+}
+
+// withRemediation appends the remediation of the errorClass to the detail of a diagnostic.
+func withRemediation(class errorClass, detail string) string {
+	remediation, ok := remediations[class]
+	if !ok {
+		return detail
+	}
+	return detail + "\n\nWhat to try: " + remediation
+}
+
+const retryableNote = "\n\nThis error is retryable: It is likely temporary, so re-running may succeed. See also `max_retries`."
+const fatalNote = "\n\nThis error is fatal: Re-running won't help, unless the configuration or the IP information provider changes."
+
+// withRetryability appends to the detail of a diagnostic, whether re-running may help. Transient failures are
+// retried, see max_retries, while fatal ones persist until the configuration or the IP information provider changes.
+func withRetryability(transient bool, detail string) string {
+	if transient {
+		return detail + retryableNote
+	}
+	return detail + fatalNote
+}
+
+// classifyError determines the errorClass of an error, which occurred while contacting the IP information provider.
+func classifyError(err error) errorClass {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var recordHeaderErr tls.RecordHeaderError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	switch {
+	case isNoRouteError(err):
+		return errorClassNoRoute
+	case errors.As(err, &dnsErr):
+		return errorClassDNS
+	case errors.As(err, &recordHeaderErr), errors.As(err, &certificateInvalidErr), errors.As(err, &unknownAuthorityErr), errors.As(err, &hostnameErr),
+		errors.Is(err, errSPKIPinMismatch):
+		return errorClassTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return errorClassTimeout
+	default:
+		return errorClassUnknown
+	}
+}
+
+// isNoRouteError reports whether err was caused by a missing network route to the destination.
+// Such errors don't get better by waiting, so they are reported right away. See routeCheckDialer.
+func isNoRouteError(err error) bool {
+	return errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH)
+}
+
+// isTransientError reports whether err may not occur again, if the request is retried,
+// i.e. whether the connection failed or timed out.
+func isTransientError(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+
+	switch {
+	case isNoRouteError(err), errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET):
+		return true
+	case errors.As(err, &opErr):
+		return opErr.Op == "dial" || opErr.Timeout()
+	default:
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsNoRouteError(t *testing.T) {
+	wrap := func(errno syscall.Errno) error {
+		return &url.Error{Op: "Get", URL: "https://ifconfig.co/json", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errno)}}
+	}
+
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"network unreachable": {wrap(syscall.ENETUNREACH), true},
+		"host unreachable":    {wrap(syscall.EHOSTUNREACH), true},
+		"connection refused":  {wrap(syscall.ECONNREFUSED), false},
+		"other":               {errors.New("other"), false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if isNoRouteError(test.err) != test.expected {
+				t.Errorf("expected %t for '%s'", test.expected, test.err)
+			}
+		})
+	}
+}
+
+func TestIsNoRouteErrorDial(t *testing.T) {
+	// 100::/64 is the IPv6 discard prefix (RFC 6666), which usually has no route.
+	timeout := 10 * time.Second
+	start := time.Now()
+	_, err := (&net.Dialer{Timeout: timeout}).DialContext(context.Background(), "tcp6", "[100::1]:80")
+	if err == nil || !isNoRouteError(err) {
+		t.Skipf("the discard prefix is routable in this environment: %v", err)
+	}
+	if time.Since(start) >= timeout {
+		t.Errorf("expected the dial to fail before the timeout, took %s", time.Since(start))
+	}
+}
+
+func TestRouteCheckDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	dialer := routeCheckDialer{dialer: &net.Dialer{Timeout: 10 * time.Second, LocalAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}}}
+	conn, err := dialer.DialContext(context.Background(), "tcp4", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	conn.Close()
+
+	// 100::/64 is the IPv6 discard prefix (RFC 6666), which usually has no route.
+	if conn, err := net.Dial("udp6", "[100::1]:80"); err == nil {
+		conn.Close()
+		t.Skip("the discard prefix is routable in this environment")
+	}
+
+	start := time.Now()
+	_, err = routeCheckDialer{dialer: &net.Dialer{Timeout: 10 * time.Second}}.DialContext(context.Background(), "tcp6", "[100::1]:80")
+	if err == nil || !isNoRouteError(err) {
+		t.Errorf("expected a missing route, got: %v", err)
+	}
+	if time.Since(start) >= time.Second {
+		t.Errorf("expected the missing route to be reported right away, took %s", time.Since(start))
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected errorClass
+	}{
+		"no route": {&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ENETUNREACH)}, errorClassNoRoute},
+		"dns":      {&url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "ifconfig.invalid", IsNotFound: true}}}, errorClassDNS},
+		"tls":      {&url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, errorClassTLS},
+		"spki pin": {&url.Error{Op: "Get", Err: errSPKIPinMismatch}, errorClassTLS},
+		"timeout":  {&url.Error{Op: "Get", Err: context.DeadlineExceeded}, errorClassTimeout},
+		"refused":  {&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, errorClassUnknown},
+		"other":    {errors.New("other"), errorClassUnknown},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			class := classifyError(test.err)
+			if class != test.expected {
+				t.Errorf("expected '%s' for '%s', got '%s'", test.expected, test.err, class)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"no route": {&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ENETUNREACH)}, false},
+		"dns":      {&url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "ifconfig.invalid", IsNotFound: true}}}, false},
+		"tls":      {&url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, false},
+		"timeout":  {&url.Error{Op: "Get", Err: context.DeadlineExceeded}, true},
+		"refused":  {&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		"reset":    {&url.Error{Op: "Get", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		"other":    {errors.New("other"), false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if transient := isTransientError(test.err); transient != test.expected {
+				t.Errorf("expected %t for '%s', got %t", test.expected, test.err, transient)
+			}
+		})
+	}
+}
+
+func TestWithRetryability(t *testing.T) {
+	if detail := withRetryability(true, "detail"); !strings.HasPrefix(detail, "detail\n\n") || !strings.Contains(detail, "retryable") {
+		t.Errorf("expected a retryable error, got '%s'", detail)
+	}
+	if detail := withRetryability(false, "detail"); !strings.HasPrefix(detail, "detail\n\n") || !strings.Contains(detail, "fatal") {
+		t.Errorf("expected a fatal error, got '%s'", detail)
+	}
+}
+
+func TestWithRemediation(t *testing.T) {
+	tests := map[errorClass]string{
+		errorClassTimeout:         "Increase `timeout`, `request_timeout` or `total_timeout`",
+		errorClassRateLimited:     "Lower `rate_limit_rate` and `rate_limit_burst`",
+		errorClassDNS:             "Check the `provider_url` and the DNS configuration",
+		errorClassNoRoute:         "Check the network connection",
+		errorClassTLS:             "whether a proxy intercepts the connection",
+		errorClassParse:           "not e.g. to a captive portal",
+		errorClassInvalidSourceIP: "The `source_ip` must be an IP",
+	}
+
+	for class, expected := range tests {
+		t.Run(string(class), func(t *testing.T) {
+			detail := withRemediation(class, "Something failed.")
+			if !strings.HasPrefix(detail, "Something failed.\n\nWhat to try: ") || !strings.Contains(detail, expected) {
+				t.Errorf("expected the remediation to contain '%s', got '%s'", expected, detail)
+			}
+		})
+	}
+
+	if withRemediation(errorClassUnknown, "Something failed.") != "Something failed." {
+		t.Errorf("expected no remediation for unknown errors")
+	}
+}
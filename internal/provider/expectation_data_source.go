@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type ExpectationDataSource struct {
+	provider *ProviderModel
+}
+
+func NewExpectationDataSource() datasource.DataSource {
+	return &ExpectationDataSource{}
+}
+
+func (d ExpectationDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_expectation"
+}
+
+func (d ExpectationDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Asserts that the current public IP is within the expected ranges, and fails the plan otherwise. This guards against applying from an unexpected network, e.g. without the VPN.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"expected_cidrs": {
+				MarkdownDescription: "The ranges, e.g. `203.0.113.0/24` or `2001:db8::/32`, of which one must contain the current public IP.",
+				Required:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"ip": {
+				MarkdownDescription: "The current public IP as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"matched_cidr": {
+				MarkdownDescription: "The first of the `expected_cidrs`, which contains the IP.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *ExpectationDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type ExpectationDataSourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	ExpectedCIDRs []string     `tfsdk:"expected_cidrs"`
+	IP            types.String `tfsdk:"ip"`
+	IPVersion     types.String `tfsdk:"ip_version"`
+	MatchedCIDR   types.String `tfsdk:"matched_cidr"`
+}
+
+func (d ExpectationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data ExpectationDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(data.ExpectedCIDRs) == 0 {
+		resp.Diagnostics.AddError("Missing expected_cidrs", "The expected_cidrs must contain at least one CIDR.")
+		return
+	}
+	expectedPrefixes := make([]netaddr.IPPrefix, 0, len(data.ExpectedCIDRs))
+	for _, cidr := range data.ExpectedCIDRs {
+		prefix, err := netaddr.ParseIPPrefix(cidr)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid expected_cidrs", fmt.Sprintf("The CIDR '%s' can't be parsed: %s", cidr, err))
+			return
+		}
+		expectedPrefixes = append(expectedPrefixes, prefix)
+	}
+
+	client := newHTTPClient(d.provider, "tcp", netaddr.IP{})
+	result, diags := fetchIPFromProviders(ctx, d.provider, client, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip := result.ip.Unmap()
+	var matched *netaddr.IPPrefix
+	for i := range expectedPrefixes {
+		if expectedPrefixes[i].Contains(ip) {
+			matched = &expectedPrefixes[i]
+			break
+		}
+	}
+	if matched == nil {
+		log.Printf("IP outside of the expected CIDRs 🚨: %s", ip)
+		resp.Diagnostics.AddError("Unexpected public IP", fmt.Sprintf("The current public IP '%s' is not within any of the expected_cidrs: %s. Make sure that Terraform runs from the expected network, e.g. through the VPN.", ip, strings.Join(data.ExpectedCIDRs, ", ")))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+	data.MatchedCIDR = types.String{Value: matched.String()}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
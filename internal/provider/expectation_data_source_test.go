@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestExpectationDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	expectedCIDRs := func(cidrs ...string) map[string]tftypes.Value {
+		values := make([]tftypes.Value, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			values = append(values, tftypes.NewValue(tftypes.String, cidr))
+		}
+		return map[string]tftypes.Value{"expected_cidrs": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values)}
+	}
+
+	resp := testReadDataSource(t, NewExpectationDataSource, providerData, expectedCIDRs("2001:db8::/32", "203.0.113.0/24", "203.0.113.4/32"))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data ExpectationDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.MatchedCIDR.Value != "203.0.113.0/24" || data.IPVersion.Value != IPVersion4 {
+		t.Errorf("unexpected expectation: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewExpectationDataSource, providerData, expectedCIDRs("198.51.100.0/24"))
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "203.0.113.4") {
+		t.Errorf("expected an error for an IP outside of the expected CIDRs, got %v", resp.Diagnostics)
+	}
+
+	resp = testReadDataSource(t, NewExpectationDataSource, providerData, expectedCIDRs("203.0.113.0"))
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for an invalid CIDR")
+	}
+}
@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const DefaultFullParallelism = 4
+
+type FullDataSource struct {
+	provider *ProviderModel
+}
+
+func NewFullDataSource() datasource.DataSource {
+	return &FullDataSource{}
+}
+
+func (d FullDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_full"
+}
+
+func (d FullDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The public and the local IPv4 and IPv6 of this host, and whether they are translated by a NAT. A missing IP stack results in a warning instead of an error.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"parallelism": {
+				MarkdownDescription: fmt.Sprintf("The maximum number of concurrent lookups. The rate limit of the provider applies nevertheless. Defaults to `%d`.", DefaultFullParallelism),
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"public_ipv4": {
+				MarkdownDescription: "The public IPv4 as returned by the IP information provider. `null` if there is no IPv4 connectivity.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"public_ipv6": {
+				MarkdownDescription: "The public IPv6 as returned by the IP information provider. `null` if there is no IPv6 connectivity.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"local_ipv4": {
+				MarkdownDescription: "The local IPv4, which is used to reach the IP information provider. `null` if there is no IPv4 route.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"local_ipv6": {
+				MarkdownDescription: "The local IPv6, which is used to reach the IP information provider. `null` if there is no IPv6 route.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"is_behind_nat_v4": {
+				MarkdownDescription: "`true` if `public_ipv4` differs from `local_ipv4`. `null` if either of them is unknown.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_behind_nat_v6": {
+				MarkdownDescription: "`true` if `public_ipv6` differs from `local_ipv6`. `null` if either of them is unknown.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+		},
+	}, nil
+}
+
+func (d *FullDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type FullDataSourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Parallelism   types.Int64  `tfsdk:"parallelism"`
+	PublicIPv4    types.String `tfsdk:"public_ipv4"`
+	PublicIPv6    types.String `tfsdk:"public_ipv6"`
+	LocalIPv4     types.String `tfsdk:"local_ipv4"`
+	LocalIPv6     types.String `tfsdk:"local_ipv6"`
+	IsBehindNATv4 types.Bool   `tfsdk:"is_behind_nat_v4"`
+	IsBehindNATv6 types.Bool   `tfsdk:"is_behind_nat_v6"`
+}
+
+func (d FullDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data FullDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parallelism := DefaultFullParallelism
+	if !data.Parallelism.Null {
+		if data.Parallelism.Value <= 0 {
+			resp.Diagnostics.AddError("Invalid parallelism", fmt.Sprintf("The parallelism value '%d' must be bigger than 0", data.Parallelism.Value))
+			return
+		}
+		parallelism = int(data.Parallelism.Value)
+	}
+
+	var publicIPv4, publicIPv6, localIPv4, localIPv6 netaddr.IP
+	lookups := []func() diag.Diagnostics{
+		func() (diags diag.Diagnostics) {
+			publicIPv4, diags = d.lookupPublicIP(ctx, "tcp4")
+			return diags
+		},
+		func() (diags diag.Diagnostics) {
+			publicIPv6, diags = d.lookupPublicIP(ctx, "tcp6")
+			return diags
+		},
+		func() (diags diag.Diagnostics) {
+			localIPv4, diags = d.lookupLocalIP(ctx, "udp4")
+			return diags
+		},
+		func() (diags diag.Diagnostics) {
+			localIPv6, diags = d.lookupLocalIP(ctx, "udp6")
+			return diags
+		},
+	}
+
+	log.Printf("looking up the public and local IPs with a parallelism of %d 🔍", parallelism)
+
+	lookupDiags := make([]diag.Diagnostics, len(lookups))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, lookup := range lookups {
+		wg.Add(1)
+		go func(i int, lookup func() diag.Diagnostics) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			lookupDiags[i] = lookup()
+		}(i, lookup)
+	}
+	wg.Wait()
+
+	for _, diags := range lookupDiags {
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if publicIPv4.IsZero() && publicIPv6.IsZero() {
+		noPublicIP(&resp.Diagnostics, "See the warnings for details.")
+		return
+	}
+
+	data.PublicIPv4 = ipValue(publicIPv4)
+	data.PublicIPv6 = ipValue(publicIPv6)
+	data.LocalIPv4 = ipValue(localIPv4)
+	data.LocalIPv6 = ipValue(localIPv6)
+	data.IsBehindNATv4 = isBehindNAT(publicIPv4, localIPv4)
+	data.IsBehindNATv6 = isBehindNAT(publicIPv6, localIPv6)
+
+	var ids []string
+	for _, ip := range []netaddr.IP{publicIPv4, publicIPv6} {
+		if !ip.IsZero() {
+			ids = append(ids, ip.String())
+		}
+	}
+	data.ID = types.String{Value: strings.Join(ids, ",")}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookupPublicIP fetches the public IP over the given network.
+// Errors are reported as warnings, as the IP stack may be missing.
+func (d FullDataSource) lookupPublicIP(ctx context.Context, network string) (netaddr.IP, diag.Diagnostics) {
+	result, diags := fetchIPOverNetwork(ctx, d.provider, network)
+	if diags.HasError() {
+		return netaddr.IP{}, warningsOnly(diags, fmt.Sprintf("The public IP could not be determined over '%s'", network))
+	}
+
+	return result.ip, diags
+}
+
+// lookupLocalIP determines the local IP, which is used to reach the IP information provider over the given network.
+func (d FullDataSource) lookupLocalIP(ctx context.Context, network string) (netaddr.IP, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ip, err := localIP(ctx, d.provider, network)
+	if err != nil {
+		log.Printf("Could not determine the local IP over '%s' 🚨: %s", network, err)
+		diags.AddWarning("Unable to determine the local IP", fmt.Sprintf("The local IP could not be determined over '%s': %s", network, err))
+		return netaddr.IP{}, diags
+	}
+
+	return ip, diags
+}
+
+// warningsOnly converts the errors to warnings and prefixes their details.
+func warningsOnly(diags diag.Diagnostics, prefix string) diag.Diagnostics {
+	var warnings diag.Diagnostics
+	for _, d := range diags {
+		warnings.AddWarning(d.Summary(), fmt.Sprintf("%s: %s", prefix, d.Detail()))
+	}
+	return warnings
+}
+
+func ipValue(ip netaddr.IP) types.String {
+	if ip.IsZero() {
+		return types.String{Null: true}
+	}
+	return types.String{Value: ip.String()}
+}
+
+func isBehindNAT(publicIP netaddr.IP, localIP netaddr.IP) types.Bool {
+	if publicIP.IsZero() || localIP.IsZero() {
+		return types.Bool{Null: true}
+	}
+	return types.Bool{Value: publicIP != localIP}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testDualStackProviderData serves the handler on the IPv4 and the IPv6 loopback
+// under the hostname 'ipinfo.test', which resolves to the given addresses.
+func testDualStackProviderData(t *testing.T, handler http.HandlerFunc, addrs ...string) *ProviderModel {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "[::]:0")
+	if err != nil {
+		t.Skipf("no dual-stack listener available: %s", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	serverURL, _ := url.Parse(server.URL)
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, "http://ipinfo.test:"+serverURL.Port()),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"dns_cache_ttl":    tftypes.NewValue(tftypes.String, "1m"),
+	})
+	providerData.dnsCache.lookup = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if host != "ipinfo.test" {
+			return nil, fmt.Errorf("unexpected host '%s'", host)
+		}
+		var ipAddrs []net.IPAddr
+		for _, addr := range addrs {
+			ipAddrs = append(ipAddrs, net.IPAddr{IP: net.ParseIP(addr)})
+		}
+		return ipAddrs, nil
+	}
+	return providerData
+}
+
+func TestFullDataSourceDualStack(t *testing.T) {
+	// IPv4 is translated by a NAT, IPv6 is not.
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		ip := net.ParseIP(host)
+		if ip.To4() != nil {
+			_, _ = fmt.Fprint(w, `{"ip":"203.0.113.1"}`)
+		} else {
+			_, _ = fmt.Fprintf(w, `{"ip":"%s"}`, ip)
+		}
+	}, "127.0.0.1", "::1")
+
+	resp := testReadDataSource(t, NewFullDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data FullDataSourceModel
+	resp.State.Get(context.Background(), &data)
+
+	if data.PublicIPv4.Value != "203.0.113.1" || data.LocalIPv4.Value != "127.0.0.1" || !data.IsBehindNATv4.Value {
+		t.Errorf("unexpected IPv4 information: %+v", data)
+	}
+	if data.PublicIPv6.Value != "::1" || data.LocalIPv6.Value != "::1" || data.IsBehindNATv6.Null || data.IsBehindNATv6.Value {
+		t.Errorf("unexpected IPv6 information: %+v", data)
+	}
+	if data.ID.Value != "203.0.113.1,::1" {
+		t.Errorf("unexpected id '%s'", data.ID.Value)
+	}
+}
+
+func TestFullDataSourceMissingFamily(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"203.0.113.1"}`)
+	}, "127.0.0.1")
+
+	resp := testReadDataSource(t, NewFullDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() == 0 {
+		t.Errorf("expected a warning about the missing IPv6")
+	}
+
+	var data FullDataSourceModel
+	resp.State.Get(context.Background(), &data)
+
+	if data.PublicIPv4.Value != "203.0.113.1" || !data.IsBehindNATv4.Value {
+		t.Errorf("unexpected IPv4 information: %+v", data)
+	}
+	if !data.PublicIPv6.Null || !data.LocalIPv6.Null || !data.IsBehindNATv6.Null {
+		t.Errorf("expected no IPv6 information: %+v", data)
+	}
+}
+
+func TestFullDataSourceUnavailable(t *testing.T) {
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ip":"203.0.113.1"}`)
+	})
+
+	resp := testReadDataSource(t, NewFullDataSource, providerData, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error without any public IP")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "No public IP" {
+		t.Errorf("expected the same error as publicip_addresses, got '%s'", summary)
+	}
+}
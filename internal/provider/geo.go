@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"inet.af/netaddr"
+)
+
+// The supported geolocation backends.
+// GeoBackendEchoIP uses the IP information provider, all others are alternative geolocation APIs.
+const (
+	GeoBackendEchoIP = "echoip"
+	GeoBackendIPInfo = "ipinfo"
+	GeoBackendIPAPI  = "ipapi"
+)
+
+// DefaultGeoEndpoints are the API endpoints of the alternative geolocation backends.
+var DefaultGeoEndpoints = map[string]string{
+	GeoBackendIPInfo: "https://ipinfo.io/",
+	GeoBackendIPAPI:  "https://ipapi.co/",
+}
+
+// lookupGeo queries the alternative geolocation backend at the endpoint for the IP.
+// The result is normalized into the fields of the IP information provider's response; fields, which the backend does not provide, are empty.
+func lookupGeo(ctx context.Context, p *ProviderModel, client *http.Client, backend string, endpoint *url.URL, apiKey string, ip netaddr.IP) (*IPResponse, error) {
+	lookupURL := *endpoint
+	query := lookupURL.Query()
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", p.nextUserAgent())
+
+	switch backend {
+	case GeoBackendIPInfo:
+		lookupURL.Path = path.Join(lookupURL.Path, ip.String(), "json")
+		if apiKey != "" {
+			header.Set("Authorization", "Bearer "+apiKey)
+		}
+	case GeoBackendIPAPI:
+		lookupURL.Path = path.Join(lookupURL.Path, ip.String(), "json") + "/"
+		if apiKey != "" {
+			query.Set("key", apiKey)
+		}
+	default:
+		return nil, fmt.Errorf("the geolocation backend '%s' is not supported", backend)
+	}
+	lookupURL.RawQuery = query.Encode()
+
+	// The API key of ipapi is sent in the query, so the query is not logged.
+	log.Printf("looking up the geolocation 🔍: %s%s", lookupURL.Host, lookupURL.Path)
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", lookupURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error contains the URL, which may contain the API key.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the geolocation backend responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes))
+	decoder.UseNumber()
+
+	result := &IPResponse{IP: ip.String()}
+	switch backend {
+	case GeoBackendIPInfo:
+		var info struct {
+			City     string `json:"city"`
+			Region   string `json:"region"`
+			Country  string `json:"country"`
+			Loc      string `json:"loc"`
+			Postal   string `json:"postal"`
+			Timezone string `json:"timezone"`
+		}
+		err = decoder.Decode(&info)
+		if err != nil {
+			return nil, err
+		}
+		result.CountryISO = info.Country
+		result.RegionName = info.Region
+		result.City = info.City
+		result.ZIPCode = info.Postal
+		result.TimeZone = info.Timezone
+		// The location is e.g. `47.3667,8.5500`.
+		if latitude, longitude, found := strings.Cut(info.Loc, ","); found {
+			result.Latitude = json.Number(strings.TrimSpace(latitude))
+			result.Longitude = json.Number(strings.TrimSpace(longitude))
+		}
+	case GeoBackendIPAPI:
+		var info struct {
+			Error       bool        `json:"error"`
+			Reason      string      `json:"reason"`
+			City        string      `json:"city"`
+			Region      string      `json:"region"`
+			RegionCode  string      `json:"region_code"`
+			CountryName string      `json:"country_name"`
+			CountryCode string      `json:"country_code"`
+			Postal      string      `json:"postal"`
+			Latitude    json.Number `json:"latitude"`
+			Longitude   json.Number `json:"longitude"`
+			Timezone    string      `json:"timezone"`
+		}
+		err = decoder.Decode(&info)
+		if err != nil {
+			return nil, err
+		}
+		// Errors, e.g. for reserved IPs, are reported with the status code 200.
+		if info.Error {
+			return nil, fmt.Errorf("the geolocation backend responded with an error: %s", info.Reason)
+		}
+		result.Country = info.CountryName
+		result.CountryISO = info.CountryCode
+		result.RegionName = info.Region
+		result.RegionCode = info.RegionCode
+		result.City = info.City
+		result.ZIPCode = info.Postal
+		result.Latitude = info.Latitude
+		result.Longitude = info.Longitude
+		result.TimeZone = info.Timezone
+	}
+
+	log.Printf("got geolocation ✅: %s", ip)
+	return result, nil
+}
@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type GeoDataSource struct {
+	provider *ProviderModel
+}
+
+func NewGeoDataSource() datasource.DataSource {
+	return &GeoDataSource{}
+}
+
+func (d GeoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_geo"
+}
+
+func (d GeoDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: fmt.Sprintf("The geolocation of the current public IP or of an arbitrary IP. `%s` uses the IP information provider, `%s` and `%s` are alternative geolocation APIs, which are useful when the geolocation of the IP information provider is outdated. Values, which the backend does not provide, are `null`.", GeoBackendEchoIP, GeoBackendIPInfo, GeoBackendIPAPI),
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to locate. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"backend": {
+				MarkdownDescription: fmt.Sprintf("The geolocation backend. Expected values: '%s', '%s', '%s'. Defaults to `%s`.", GeoBackendEchoIP, GeoBackendIPInfo, GeoBackendIPAPI, GeoBackendEchoIP),
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"endpoint": {
+				MarkdownDescription: fmt.Sprintf("The URL of the API of the geolocation backend. Defaults to `%s` for '%s' and `%s` for '%s'. Not used by '%s'.", DefaultGeoEndpoints[GeoBackendIPInfo], GeoBackendIPInfo, DefaultGeoEndpoints[GeoBackendIPAPI], GeoBackendIPAPI, GeoBackendEchoIP),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"api_key": {
+				MarkdownDescription: fmt.Sprintf("The token (ipinfo) or API key (ipapi) of the geolocation backend. Without one, the free tier with its rate limits is used. Not used by '%s'.", GeoBackendEchoIP),
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"country": {
+				MarkdownDescription: "The name of the country.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country_iso": {
+				MarkdownDescription: "The ISO code of the country.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region_name": {
+				MarkdownDescription: "The name of the region.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region_code": {
+				MarkdownDescription: "The code of the region.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"city": {
+				MarkdownDescription: "The city.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"zip_code": {
+				MarkdownDescription: "The ZIP code.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"latitude": {
+				MarkdownDescription: "The latitude.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"longitude": {
+				MarkdownDescription: "The longitude.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"time_zone": {
+				MarkdownDescription: "The time zone, e.g. `Europe/Zurich`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *GeoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type GeoDataSourceModel struct {
+	ID         types.String  `tfsdk:"id"`
+	IP         types.String  `tfsdk:"ip"`
+	Backend    types.String  `tfsdk:"backend"`
+	Endpoint   types.String  `tfsdk:"endpoint"`
+	APIKey     types.String  `tfsdk:"api_key"`
+	Country    types.String  `tfsdk:"country"`
+	CountryISO types.String  `tfsdk:"country_iso"`
+	RegionName types.String  `tfsdk:"region_name"`
+	RegionCode types.String  `tfsdk:"region_code"`
+	City       types.String  `tfsdk:"city"`
+	ZIPCode    types.String  `tfsdk:"zip_code"`
+	Latitude   types.Float64 `tfsdk:"latitude"`
+	Longitude  types.Float64 `tfsdk:"longitude"`
+	TimeZone   types.String  `tfsdk:"time_zone"`
+}
+
+func (d GeoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data GeoDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backend := GeoBackendEchoIP
+	if !data.Backend.Null && !data.Backend.Unknown {
+		backend = data.Backend.Value
+	}
+	var endpoint *url.URL
+	if backend != GeoBackendEchoIP {
+		endpointStr, ok := DefaultGeoEndpoints[backend]
+		if !ok {
+			resp.Diagnostics.AddError("Invalid backend", fmt.Sprintf("The backend '%s' must be one of '%s', '%s' or '%s'.", backend, GeoBackendEchoIP, GeoBackendIPInfo, GeoBackendIPAPI))
+			return
+		}
+		if !data.Endpoint.Null {
+			endpointStr = data.Endpoint.Value
+		}
+		var err error
+		endpoint, err = url.Parse(endpointStr)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to parse the endpoint", fmt.Sprintf("The endpoint value '%s' can't be parsed: %s", endpointStr, err))
+			return
+		}
+	}
+
+	ip, result, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var respData *IPResponse
+	if result != nil {
+		respData = result.respData
+	} else if backend == GeoBackendEchoIP {
+		client := newHTTPClient(d.provider, "tcp", netaddr.IP{})
+		result, diags := fetchIPFromProviders(ctx, d.provider, client, url.Values{"ip": {ip.String()}})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		respData = result.respData
+	}
+
+	if backend != GeoBackendEchoIP {
+		var err error
+		respData, err = lookupGeo(ctx, d.provider, newLookupHTTPClient(d.provider), backend, endpoint, data.APIKey.Value, ip)
+		if err != nil {
+			log.Printf("Geolocation error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error looking up the geolocation", fmt.Sprintf("The geolocation of '%s' could not be looked up with '%s': %s", ip, backend, err))
+			return
+		}
+	}
+
+	data.ID = types.String{Value: backend + "$" + ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.Backend = types.String{Value: backend}
+	data.Country = optionalString(respData.Country)
+	data.CountryISO = optionalString(respData.CountryISO)
+	data.RegionName = optionalString(respData.RegionName)
+	data.RegionCode = optionalString(respData.RegionCode)
+	data.City = optionalString(respData.City)
+	data.ZIPCode = optionalString(respData.ZIPCode)
+	data.Latitude, _ = coordinateValues(respData.Latitude)
+	data.Longitude, _ = coordinateValues(respData.Longitude)
+	data.TimeZone = optionalString(respData.TimeZone)
+
+	logged := data
+	if !logged.APIKey.Null {
+		logged.APIKey = types.String{Value: "(sensitive)"}
+	}
+	log.Printf("got to state update ✅: %+v", logged)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGeoDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/json" && r.URL.Query().Get("ip") == "":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4","country":"Switzerland","country_iso":"CH","city":"Zurich","latitude":47.3667,"longitude":8.55,"time_zone":"Europe/Zurich"}`))
+		case r.URL.Path == "/json" && r.URL.Query().Get("ip") == "198.51.100.7":
+			_, _ = w.Write([]byte(`{"ip":"198.51.100.7","country":"Germany","country_iso":"DE"}`))
+		case r.URL.Path == "/ipinfo/203.0.113.4/json" && r.Header.Get("Authorization") == "Bearer secret":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4","city":"Bern","region":"Bern","country":"CH","loc":"46.9481,7.4474","postal":"3000","timezone":"Europe/Zurich"}`))
+		case r.URL.Path == "/ipapi/198.51.100.7/json/" && r.URL.Query().Get("key") == "secret":
+			_, _ = w.Write([]byte(`{"ip":"198.51.100.7","city":"Berlin","region":"Land Berlin","region_code":"BE","country_name":"Germany","country_code":"DE","postal":"10115","latitude":52.5244,"longitude":13.4105,"timezone":"Europe/Berlin"}`))
+		case r.URL.Path == "/ipapi/192.0.2.1/json/":
+			_, _ = w.Write([]byte(`{"ip":"192.0.2.1","error":true,"reason":"Reserved IP Address","reserved":true}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	var data GeoDataSourceModel
+	resp := testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	resp.State.Get(context.Background(), &data)
+	if data.Backend.Value != GeoBackendEchoIP || data.IP.Value != "203.0.113.4" || data.City.Value != "Zurich" || data.Latitude.Value != 47.3667 || !data.RegionCode.Null {
+		t.Errorf("unexpected echoip geolocation: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "198.51.100.7"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	resp.State.Get(context.Background(), &data)
+	if data.CountryISO.Value != "DE" || !data.City.Null || !data.Latitude.Null {
+		t.Errorf("unexpected echoip geolocation of an arbitrary IP: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{
+		"backend":  tftypes.NewValue(tftypes.String, GeoBackendIPInfo),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/ipinfo/"),
+		"api_key":  tftypes.NewValue(tftypes.String, "secret"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.City.Value != "Bern" || data.CountryISO.Value != "CH" || !data.Country.Null || data.Latitude.Value != 46.9481 || data.Longitude.Value != 7.4474 || data.ZIPCode.Value != "3000" {
+		t.Errorf("unexpected ipinfo geolocation: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{
+		"ip":       tftypes.NewValue(tftypes.String, "198.51.100.7"),
+		"backend":  tftypes.NewValue(tftypes.String, GeoBackendIPAPI),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/ipapi/"),
+		"api_key":  tftypes.NewValue(tftypes.String, "secret"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	resp.State.Get(context.Background(), &data)
+	if data.Country.Value != "Germany" || data.RegionCode.Value != "BE" || data.City.Value != "Berlin" || data.Longitude.Value != 13.4105 || data.TimeZone.Value != "Europe/Berlin" {
+		t.Errorf("unexpected ipapi geolocation: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{
+		"ip":       tftypes.NewValue(tftypes.String, "192.0.2.1"),
+		"backend":  tftypes.NewValue(tftypes.String, GeoBackendIPAPI),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/ipapi/"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for an error response of ipapi")
+	}
+
+	resp = testReadDataSource(t, NewGeoDataSource, providerData, map[string]tftypes.Value{
+		"backend": tftypes.NewValue(tftypes.String, "unknown"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for an unknown backend")
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+	"inet.af/netaddr"
+)
+
+// geoIPDatabase is a local MaxMind database (MMDB), e.g. GeoLite2-City or GeoLite2-ASN.
+type geoIPDatabase struct {
+	reader *maxminddb.Reader
+	// geo is set for the City and Country databases, which contain the location.
+	geo bool
+	// asn is set for the ASN databases.
+	asn bool
+}
+
+// geoIPRecord contains the fields of the MaxMind City, Country and ASN databases.
+type geoIPRecord struct {
+	Country struct {
+		ISOCode           string            `maxminddb:"iso_code"`
+		IsInEuropeanUnion bool              `maxminddb:"is_in_european_union"`
+		Names             map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Postal struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"postal"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+		TimeZone  string   `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	AutonomousSystemNumber       uint64 `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+func openGeoIPDatabase(path string) (*geoIPDatabase, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseType := reader.Metadata.DatabaseType
+	db := &geoIPDatabase{
+		reader: reader,
+		geo:    strings.Contains(databaseType, "City") || strings.Contains(databaseType, "Country"),
+		asn:    strings.Contains(databaseType, "ASN"),
+	}
+	if !db.geo && !db.asn {
+		_ = reader.Close()
+		return nil, fmt.Errorf("the database type '%s' is not supported, expected a City, Country or ASN database", databaseType)
+	}
+
+	log.Printf("opened GeoIP database 🗺️: %s (%s)", path, databaseType)
+	return db, nil
+}
+
+// apply replaces the fields of the response payload, which the database provides, with the values from the database.
+// Fields, which the database has no value for, are removed rather than kept from the IP information provider.
+func (db *geoIPDatabase) apply(ip netaddr.IP, payload map[string]interface{}) error {
+	var record geoIPRecord
+	err := db.reader.Lookup(ip.IPAddr().IP, &record)
+	if err != nil {
+		return err
+	}
+
+	if db.geo {
+		var subdivisionName, subdivisionCode string
+		if len(record.Subdivisions) > 0 {
+			subdivisionName = record.Subdivisions[0].Names["en"]
+			subdivisionCode = record.Subdivisions[0].ISOCode
+		}
+
+		setPayloadField(payload, "country", record.Country.Names["en"])
+		setPayloadField(payload, "country_iso", record.Country.ISOCode)
+		setPayloadField(payload, "region_name", subdivisionName)
+		setPayloadField(payload, "region_code", subdivisionCode)
+		setPayloadField(payload, "city", record.City.Names["en"])
+		setPayloadField(payload, "zip_code", record.Postal.Code)
+		setPayloadField(payload, "latitude", geoIPCoordinate(record.Location.Latitude))
+		setPayloadField(payload, "longitude", geoIPCoordinate(record.Location.Longitude))
+		setPayloadField(payload, "time_zone", record.Location.TimeZone)
+		delete(payload, "country_eu")
+		if record.Country.ISOCode != "" {
+			payload["country_eu"] = record.Country.IsInEuropeanUnion
+		}
+	}
+
+	if db.asn {
+		var asn string
+		if record.AutonomousSystemNumber != 0 {
+			asn = fmt.Sprintf("AS%d", record.AutonomousSystemNumber)
+		}
+		setPayloadField(payload, "asn", asn)
+		setPayloadField(payload, "asn_org", record.AutonomousSystemOrganization)
+	}
+
+	log.Printf("got GeoIP record ✅: %s", ip)
+	return nil
+}
+
+// setPayloadField sets the field of the payload, or removes it if the value is empty.
+func setPayloadField(payload map[string]interface{}, field string, value interface{}) {
+	if value == nil || value == "" {
+		delete(payload, field)
+		return
+	}
+	payload[field] = value
+}
+
+// geoIPCoordinate returns the coordinate as the IP information provider would, i.e. as number, or nil if it is missing.
+func geoIPCoordinate(coordinate *float64) interface{} {
+	if coordinate == nil {
+		return nil
+	}
+	return json.Number(strconv.FormatFloat(*coordinate, 'f', -1, 64))
+}
@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+// testGeoIPDatabase writes a MaxMind database (MMDB) with the records, which are keyed by their prefix, and returns its path.
+func testGeoIPDatabase(t *testing.T, databaseType string, records map[string]map[string]interface{}) string {
+	t.Helper()
+
+	type record struct {
+		node int
+		data int
+	}
+	// Every node has a left (0) and right (1) record. -1 means empty.
+	nodes := [][2]record{{{-1, -1}, {-1, -1}}}
+	var data bytes.Buffer
+
+	for prefixStr, value := range records {
+		prefix := netaddr.MustParseIPPrefix(prefixStr)
+		bits := int(prefix.Bits())
+		ip := prefix.IP().As16()
+		if prefix.IP().Is4() {
+			// IPv4 is stored in the IPv6 tree under ::/96.
+			ip = [16]byte{}
+			ip4 := prefix.IP().As4()
+			copy(ip[12:], ip4[:])
+			bits += 96
+		}
+
+		dataOffset := data.Len()
+		testMMDBEncode(t, &data, value)
+
+		node := 0
+		for i := 0; i < bits; i++ {
+			bit := (ip[i/8] >> (7 - i%8)) & 1
+			if i == bits-1 {
+				nodes[node][bit] = record{node: -1, data: dataOffset}
+				break
+			}
+			if nodes[node][bit].node < 0 {
+				nodes = append(nodes, [2]record{{-1, -1}, {-1, -1}})
+				nodes[node][bit] = record{node: len(nodes) - 1, data: -1}
+			}
+			node = nodes[node][bit].node
+		}
+	}
+
+	var db bytes.Buffer
+	nodeCount := uint32(len(nodes))
+	for _, node := range nodes {
+		for _, r := range node {
+			value := nodeCount
+			if r.node >= 0 {
+				value = uint32(r.node)
+			} else if r.data >= 0 {
+				value = nodeCount + 16 + uint32(r.data)
+			}
+			_ = binary.Write(&db, binary.BigEndian, value)
+		}
+	}
+	db.Write(make([]byte, 16))
+	db.Write(data.Bytes())
+	db.WriteString("\xab\xcd\xefMaxMind.com")
+	testMMDBEncode(t, &db, map[string]interface{}{
+		"binary_format_major_version": uint32(2),
+		"binary_format_minor_version": uint32(0),
+		"build_epoch":                 uint32(0),
+		"database_type":               databaseType,
+		"description":                 map[string]interface{}{},
+		"ip_version":                  uint32(6),
+		"languages":                   []interface{}{},
+		"node_count":                  nodeCount,
+		"record_size":                 uint32(32),
+	})
+
+	path := filepath.Join(t.TempDir(), databaseType+".mmdb")
+	err := os.WriteFile(path, db.Bytes(), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testMMDBEncode encodes the value in the data section format of MMDB.
+func testMMDBEncode(t *testing.T, buffer *bytes.Buffer, value interface{}) {
+	t.Helper()
+
+	control := func(dataType int, size int) {
+		if size >= 29+256 {
+			t.Fatalf("size %d is not supported", size)
+		}
+		sizeBits := size
+		if size >= 29 {
+			sizeBits = 29
+		}
+		if dataType <= 7 {
+			buffer.WriteByte(byte(dataType<<5 | sizeBits))
+		} else {
+			buffer.WriteByte(byte(sizeBits))
+			buffer.WriteByte(byte(dataType - 7))
+		}
+		if size >= 29 {
+			buffer.WriteByte(byte(size - 29))
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		control(2, len(v))
+		buffer.WriteString(v)
+	case float64:
+		control(3, 8)
+		_ = binary.Write(buffer, binary.BigEndian, math.Float64bits(v))
+	case uint32:
+		control(6, 4)
+		_ = binary.Write(buffer, binary.BigEndian, v)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		control(14, size)
+	case map[string]interface{}:
+		control(7, len(v))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			testMMDBEncode(t, buffer, key)
+			testMMDBEncode(t, buffer, v[key])
+		}
+	case []interface{}:
+		control(11, len(v))
+		for _, element := range v {
+			testMMDBEncode(t, buffer, element)
+		}
+	default:
+		t.Fatalf("type %T is not supported", value)
+	}
+}
+
+func TestGeoIPDatabase(t *testing.T) {
+	cityPath := testGeoIPDatabase(t, "GeoLite2-City", map[string]map[string]interface{}{
+		"203.0.113.0/24": {
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Zurich"}},
+			"country":      map[string]interface{}{"iso_code": "CH", "is_in_european_union": false, "names": map[string]interface{}{"en": "Switzerland", "de": "Schweiz"}},
+			"location":     map[string]interface{}{"latitude": 47.3667, "longitude": 8.55, "time_zone": "Europe/Zurich"},
+			"postal":       map[string]interface{}{"code": "8001"},
+			"subdivisions": []interface{}{map[string]interface{}{"iso_code": "ZH", "names": map[string]interface{}{"en": "Zurich"}}},
+		},
+		"2001:db8::/32": {
+			"country": map[string]interface{}{"iso_code": "DE", "is_in_european_union": true, "names": map[string]interface{}{"en": "Germany"}},
+		},
+	})
+
+	db, err := openGeoIPDatabase(cityPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !db.geo || db.asn {
+		t.Errorf("expected a geo database, got %+v", db)
+	}
+
+	respData := testGeoIPApply(t, db, "203.0.113.4", `{"ip":"203.0.113.4","country":"Stale","city":"Stale","asn":"AS64496"}`)
+	if respData.Country != "Switzerland" || respData.CountryISO != "CH" || respData.CountryEU || !respData.has("country_eu") || respData.City != "Zurich" || respData.RegionCode != "ZH" ||
+		respData.ZIPCode != "8001" || respData.Latitude != "47.3667" || respData.Longitude != "8.55" || respData.TimeZone != "Europe/Zurich" || respData.ASN != "AS64496" {
+		t.Errorf("unexpected response after the City lookup: %+v", respData)
+	}
+
+	respData = testGeoIPApply(t, db, "2001:db8::1", `{"ip":"2001:db8::1","city":"Stale","latitude":1.5}`)
+	if respData.CountryISO != "DE" || !respData.CountryEU || respData.has("city") || respData.has("latitude") {
+		t.Errorf("unexpected response after the City lookup without city: %+v", respData)
+	}
+
+	asnPath := testGeoIPDatabase(t, "GeoLite2-ASN", map[string]map[string]interface{}{
+		"203.0.113.0/24": {"autonomous_system_number": uint32(64500), "autonomous_system_organization": "Example AG"},
+	})
+	db, err = openGeoIPDatabase(asnPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	respData = testGeoIPApply(t, db, "203.0.113.4", `{"ip":"203.0.113.4","country":"Switzerland","asn":"AS64496","asn_org":"Stale"}`)
+	if respData.ASN != "AS64500" || respData.ASNOrg != "Example AG" || respData.Country != "Switzerland" {
+		t.Errorf("unexpected response after the ASN lookup: %+v", respData)
+	}
+
+	respData = testGeoIPApply(t, db, "198.51.100.7", `{"ip":"198.51.100.7","asn":"AS64496","asn_org":"Stale"}`)
+	if respData.has("asn") || respData.has("asn_org") {
+		t.Errorf("unexpected response after the ASN lookup of an unknown IP: %+v", respData)
+	}
+
+	_, err = openGeoIPDatabase(testGeoIPDatabase(t, "GeoIP2-Anonymous-IP", nil))
+	if err == nil {
+		t.Error("expected an error for an unsupported database type")
+	}
+}
+
+func testGeoIPApply(t *testing.T, db *geoIPDatabase, ip string, body string) *IPResponse {
+	t.Helper()
+
+	payload, err := decodePayload(strings.NewReader(body), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.apply(netaddr.MustParseIP(ip), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respData, err := newIPResponse(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return respData
+}
+
+func TestIpAddressDataSourceGeoIPDatabase(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4","country":"Germany","country_iso":"DE","city":"Berlin","latitude":52.5244,"longitude":13.4105}`))
+	})
+	path := testGeoIPDatabase(t, "GeoLite2-City", map[string]map[string]interface{}{
+		"203.0.113.0/24": {
+			"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Zurich"}},
+			"country": map[string]interface{}{"iso_code": "CH", "is_in_european_union": false, "names": map[string]interface{}{"en": "Switzerland"}},
+		},
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":        tftypes.NewValue(tftypes.String, server.URL),
+		"geoip_database_path": tftypes.NewValue(tftypes.String, path),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.City.Value != "Zurich" || data.CountryISO.Value != "CH" || data.CountryEU.Value || data.CountryEU.Null || !data.Latitude.Null {
+		t.Errorf("unexpected location from the GeoIP database: %+v", data)
+	}
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const DefaultHostsParallelism = 4
+
+type HostsDataSource struct {
+	provider *ProviderModel
+}
+
+func NewHostsDataSource() datasource.DataSource {
+	return &HostsDataSource{}
+}
+
+func (d HostsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosts"
+}
+
+func (d HostsDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Resolves a list of hostnames locally and looks up the information about each resulting IP at the IP information provider.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"hostnames": {
+				MarkdownDescription: "The hostnames to look up. IPs are accepted as well.",
+				Required:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"parallelism": {
+				MarkdownDescription: fmt.Sprintf("The maximum number of concurrent lookups. The rate limit of the provider applies nevertheless. Defaults to `%d`.", DefaultHostsParallelism),
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"hosts": {
+				MarkdownDescription: "The information about each host, in the same order as `hostnames`.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"hostname": {
+						MarkdownDescription: "The hostname as given in `hostnames`.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"ip": {
+						MarkdownDescription: "The IP the hostname resolved to.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"ip_version": {
+						MarkdownDescription: fmt.Sprintf("Whether the IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"scope": {
+						MarkdownDescription: fmt.Sprintf("The scope of the IP. Expected values: '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s'", IPScopeGlobal, IPScopePrivate, IPScopeUniqueLocal, IPScopeLinkLocal, IPScopeLoopback, IPScopeMulticast, IPScopeUnspecified, IPUnknown),
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"asn_id": {
+						MarkdownDescription: "The ASN as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"asn_org": {
+						MarkdownDescription: "The organisation to which the ASN is registered to as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"country": {
+						MarkdownDescription: "The country as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"country_iso": {
+						MarkdownDescription: "The ISO code of the country as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"city": {
+						MarkdownDescription: "The city as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"latitude": {
+						MarkdownDescription: "The latitude as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.Float64Type,
+					},
+					"longitude": {
+						MarkdownDescription: "The longitude as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.Float64Type,
+					},
+					"time_zone": {
+						MarkdownDescription: "The time zone as returned by the IP information provider.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *HostsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type HostsDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Hostnames   []string     `tfsdk:"hostnames"`
+	Parallelism types.Int64  `tfsdk:"parallelism"`
+	Hosts       []HostModel  `tfsdk:"hosts"`
+}
+
+type HostModel struct {
+	Hostname   types.String  `tfsdk:"hostname"`
+	IP         types.String  `tfsdk:"ip"`
+	IPVersion  types.String  `tfsdk:"ip_version"`
+	Scope      types.String  `tfsdk:"scope"`
+	ASNID      types.String  `tfsdk:"asn_id"`
+	ASNOrg     types.String  `tfsdk:"asn_org"`
+	Country    types.String  `tfsdk:"country"`
+	CountryISO types.String  `tfsdk:"country_iso"`
+	City       types.String  `tfsdk:"city"`
+	Latitude   types.Float64 `tfsdk:"latitude"`
+	Longitude  types.Float64 `tfsdk:"longitude"`
+	TimeZone   types.String  `tfsdk:"time_zone"`
+}
+
+func (d HostsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data HostsDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parallelism := DefaultHostsParallelism
+	if !data.Parallelism.Null {
+		if data.Parallelism.Value <= 0 {
+			resp.Diagnostics.AddError("Invalid parallelism", fmt.Sprintf("The parallelism value '%d' must be bigger than 0", data.Parallelism.Value))
+			return
+		}
+		parallelism = int(data.Parallelism.Value)
+	}
+
+	log.Printf("looking up %d hosts with a parallelism of %d 🔍", len(data.Hostnames), parallelism)
+
+	hosts := make([]HostModel, len(data.Hostnames))
+	hostDiags := make([]diag.Diagnostics, len(data.Hostnames))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, hostname := range data.Hostnames {
+		wg.Add(1)
+		go func(i int, hostname string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			hosts[i], hostDiags[i] = d.lookupHost(ctx, hostname)
+		}(i, hostname)
+	}
+	wg.Wait()
+
+	for _, diags := range hostDiags {
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.String{Value: strings.Join(data.Hostnames, ",")}
+	data.Hosts = hosts
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (d HostsDataSource) lookupHost(ctx context.Context, hostname string) (HostModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ip, err := resolveIP(ctx, hostname, IPUnknown)
+	if err != nil {
+		log.Printf("Could not resolve '%s' 🚨: %s", hostname, err)
+		diags.AddError("Unable to resolve the hostname", fmt.Sprintf("The hostname '%s' could not be resolved: %s", hostname, err))
+		return HostModel{}, diags
+	}
+
+	client := newHTTPClient(d.provider, "tcp", netaddr.IP{})
+	result, fetchDiags := fetchIPFromProviders(ctx, d.provider, client, url.Values{"ip": {ip.String()}})
+	diags.Append(fetchDiags...)
+	if diags.HasError() {
+		return HostModel{}, diags
+	}
+
+	diags.Append(checkLookedUpIP(result, ip)...)
+	if diags.HasError() {
+		return HostModel{}, diags
+	}
+
+	respData := result.respData
+	latitude, _ := coordinateValues(respData.Latitude)
+	longitude, _ := coordinateValues(respData.Longitude)
+
+	return HostModel{
+		Hostname:   types.String{Value: hostname},
+		IP:         types.String{Value: ip.String()},
+		IPVersion:  types.String{Value: ipVersion(ip)},
+		Scope:      types.String{Value: ipScope(ip)},
+		ASNID:      types.String{Value: respData.ASN},
+		ASNOrg:     types.String{Value: respData.ASNOrg},
+		Country:    types.String{Value: respData.Country},
+		CountryISO: types.String{Value: respData.CountryISO},
+		City:       types.String{Value: respData.City},
+		Latitude:   latitude,
+		Longitude:  longitude,
+		TimeZone:   types.String{Value: respData.TimeZone},
+	}, diags
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestHostsDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"ip":"%s","asn":"AS64496","country":"Switzerland","latitude":47.5}`, r.URL.Query().Get("ip"))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	resp := testReadDataSource(t, NewHostsDataSource, providerData, map[string]tftypes.Value{
+		"hostnames": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "localhost"),
+			tftypes.NewValue(tftypes.String, "127.0.0.1"),
+		}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data HostsDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if len(data.Hosts) != 2 {
+		t.Fatalf("expected 2 hosts, got %d", len(data.Hosts))
+	}
+
+	if data.Hosts[0].Hostname.Value != "localhost" {
+		t.Errorf("expected hostname 'localhost', got '%s'", data.Hosts[0].Hostname.Value)
+	}
+	if data.Hosts[0].IP.Value != "127.0.0.1" && data.Hosts[0].IP.Value != "::1" {
+		t.Errorf("expected a loopback IP, got '%s'", data.Hosts[0].IP.Value)
+	}
+	if data.Hosts[1].IP.Value != "127.0.0.1" {
+		t.Errorf("expected IP '127.0.0.1', got '%s'", data.Hosts[1].IP.Value)
+	}
+	for _, host := range data.Hosts {
+		if host.ASNID.Value != "AS64496" || host.Country.Value != "Switzerland" || host.Latitude.Value != 47.5 {
+			t.Errorf("unexpected host information: %+v", host)
+		}
+	}
+}
+
+func TestHostsDataSourceUnresolvable(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewHostsDataSource, providerData, map[string]tftypes.Value{
+		"hostnames": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "does-not-exist.invalid"),
+		}),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestHostsDataSourceIPMismatch(t *testing.T) {
+	// The IP information provider ignores the ip query parameter and returns the IP of the client.
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4","country":"Switzerland"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewHostsDataSource, providerData, map[string]tftypes.Value{
+		"hostnames": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "127.0.0.1"),
+		}),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "instead of the requested IP '127.0.0.1'") {
+		t.Errorf("expected the IP mismatch to be reported, got: %v", resp.Diagnostics)
+	}
+}
@@ -2,39 +2,355 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"inet.af/netaddr"
 )
 
-func forceNetwork(client *http.Client, network string, sourceIP netaddr.IP) {
+const HTTPVersion1 = "1.1"
+const HTTPVersion2 = "2"
+
+// newHTTPClient creates a client for requests to the IP information provider.
+// The duration of the requests is limited by their context, see request_timeout.
+func newHTTPClient(p *ProviderModel, network string, sourceIP netaddr.IP) *http.Client {
+	return newBoundHTTPClient(p, network, sourceIP, "", "")
+}
+
+// newBoundHTTPClient creates a client like newHTTPClient, whose connections are bound to the network device and
+// made from the network namespace, if set.
+func newBoundHTTPClient(p *ProviderModel, network string, sourceIP netaddr.IP, bindDevice string, netns string) *http.Client {
+	client := newClient(p, network, sourceIP, bindDevice, netns, p.tlsConfig)
+
+	if version := networkIPVersion(network); p.ipVersionHeader != "" && version != IPUnknown {
+		client.Transport = &headerTransport{
+			transport: client.Transport,
+			name:      p.ipVersionHeader,
+			value:     version,
+		}
+	}
+	return client
+}
+
+// newLookupHTTPClient creates a client for the lookups at other services than the IP information provider, e.g. RDAP.
+// Unlike newHTTPClient, it neither presents the client certificate nor verifies the pinned_spki_hashes.
+func newLookupHTTPClient(p *ProviderModel) *http.Client {
+	return newBoundLookupHTTPClient(p, "tcp", netaddr.IP{}, "", "")
+}
+
+// newBoundLookupHTTPClient creates a client like newLookupHTTPClient, which is bound like newBoundHTTPClient.
+func newBoundLookupHTTPClient(p *ProviderModel, network string, sourceIP netaddr.IP, bindDevice string, netns string) *http.Client {
+	return newClient(p, network, sourceIP, bindDevice, netns, p.lookupTLSConfig)
+}
+
+func newClient(p *ProviderModel, network string, sourceIP netaddr.IP, bindDevice string, netns string, tlsConfig *tls.Config) *http.Client {
+	client := &http.Client{CheckRedirect: limitRedirects(p.maxRedirects)}
+	forceNetwork(client, dialSettings{
+		network:     network,
+		sourceIP:    sourceIP,
+		bindDevice:  bindDevice,
+		netns:       netns,
+		proxy:       p.proxy,
+		tlsConfig:   tlsConfig,
+		httpVersion: p.httpVersion,
+		timeout:     p.timeout,
+		dnsCache:    p.dnsCache,
+	})
+	return client
+}
+
+// limitRedirects returns a http.Client.CheckRedirect, which fails the request after maxRedirects redirects.
+func limitRedirects(maxRedirects int) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if maxRedirects == 0 {
+			return fmt.Errorf("the redirect to '%s' is not followed, see follow_redirects and max_redirects", req.URL.Redacted())
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects, see max_redirects", maxRedirects)
+		}
+		log.Printf("got redirect ↪️: %s", req.URL.Redacted())
+		return nil
+	}
+}
+
+// networkIPVersion returns the IP version the network is bound to, e.g. `v4` for `tcp4`.
+func networkIPVersion(network string) string {
+	if strings.HasSuffix(network, "4") {
+		return IPVersion4
+	}
+	if strings.HasSuffix(network, "6") {
+		return IPVersion6
+	}
+
+	return IPUnknown
+}
+
+// headerTransport sets a header on every request before passing it on to the wrapped transport.
+type headerTransport struct {
+	transport http.RoundTripper
+	name      string
+	value     string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.name, t.value)
+	log.Printf("got header ✅: %s: %s", t.name, t.value)
+	return t.transport.RoundTrip(req)
+}
+
+// dialSettings define how the connections to the IP information provider are established.
+type dialSettings struct {
+	network  string
+	sourceIP netaddr.IP
+	// bindDevice is the network device the connections are bound to, if set. See bindToDevice.
+	bindDevice string
+	// proxy returns the proxy for a request, like http.Transport.Proxy.
+	proxy func(*http.Request) (*url.URL, error)
+	// httpVersion is the HTTP version, see HTTPVersion1 and HTTPVersion2.
+	httpVersion string
+	// tlsConfig is used for the TLS connections, if set.
+	tlsConfig *tls.Config
+	// netns is the path of the network namespace the connections are made from, if set. See netnsDialer.
+	netns   string
+	timeout time.Duration
+	// dnsCache is used to resolve the host, if set.
+	dnsCache *dnsCache
+}
+
+func forceNetwork(client *http.Client, settings dialSettings) {
+	network := settings.network
+	sourceIP := settings.sourceIP
+
 	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.proxy != nil {
+		transport.Proxy = settings.proxy
+	}
+	if settings.tlsConfig != nil {
+		transport.TLSClientConfig = settings.tlsConfig.Clone()
+	}
+	if settings.httpVersion == HTTPVersion1 {
+		// A non-nil, empty TLSNextProto disables HTTP/2.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
 	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
 		// Mirrors http.DefaultTransport DialContext,
 		// with the exception that 'network' and
 		// eventually 'LocalAddr' are overwritten.
 		// Based upon https://stackoverflow.com/a/69307638/172132
 
+		if socketPath, ok := unixSocketPath(addr); ok {
+			log.Printf("Dial 🌐: Unix domain socket: '%s'", socketPath)
+			dialer := &net.Dialer{Timeout: settings.timeout}
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+
 		log.Printf("Dial 🌐: Network: '%s' LocalAddr: '%s'", network, sourceIP.String())
 
 		var dialer *net.Dialer
 		if sourceIP.IsZero() {
 			dialer = &net.Dialer{
-				Timeout:   30 * time.Second,
+				Timeout:   settings.timeout,
 				KeepAlive: 30 * time.Second,
 			}
 		} else {
 			dialer = &net.Dialer{
-				Timeout:   30 * time.Second,
+				Timeout:   settings.timeout,
 				KeepAlive: 30 * time.Second,
-				LocalAddr: &net.TCPAddr{IP: net.ParseIP(sourceIP.String())},
+				LocalAddr: &net.TCPAddr{IP: sourceIP.IPAddr().IP, Zone: sourceIP.Zone()},
 			}
 		}
-		return dialer.DialContext(ctx, network, addr)
+
+		if settings.bindDevice != "" {
+			dialer.Control = bindToDevice(settings.bindDevice)
+		}
+		routeChecked := routeCheckDialer{dialer: dialer}
+
+		if settings.netns != "" {
+			// The host is resolved outside the namespace, as the resolver may use other threads.
+			cache := settings.dnsCache
+			if cache == nil {
+				cache = newDNSCache(0)
+			}
+			return dialCached(ctx, netnsDialer{dialer: routeChecked, path: settings.netns}, cache, network, addr)
+		}
+
+		if settings.dnsCache != nil {
+			return dialCached(ctx, routeChecked, settings.dnsCache, network, addr)
+		}
+		return routeChecked.DialContext(ctx, network, addr)
 	}
 
 	client.Transport = transport
 }
+
+// errSPKIPinMismatch is returned if no certificate of the server matches the pinned_spki_hashes.
+var errSPKIPinMismatch = errors.New("no certificate matches the pinned_spki_hashes")
+
+// verifySPKIPins returns a tls.Config.VerifyConnection, which only accepts connections, if the SHA-256 hash of the
+// public key of any of the certificates presented by the server is one of the pins.
+func verifySPKIPins(pins map[[sha256.Size]byte]bool) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		for _, cert := range state.PeerCertificates {
+			if pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+		return errSPKIPinMismatch
+	}
+}
+
+// unixSocketHostSuffix marks the hosts of the requests over a Unix domain socket, see unixSocketURL.
+const unixSocketHostSuffix = ".unix.invalid"
+
+// unixSocketURL returns the URL for a request over the Unix domain socket at the path. The path is encoded into the
+// host, so that the connections to different sockets are not mixed up by the connection pool.
+func unixSocketURL(socketPath string) url.URL {
+	return url.URL{Scheme: "http", Host: hex.EncodeToString([]byte(socketPath)) + unixSocketHostSuffix}
+}
+
+// unixSocketPath returns the path of the Unix domain socket, which is encoded into the host of the address, if any.
+func unixSocketPath(addr string) (string, bool) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if !strings.HasSuffix(host, unixSocketHostSuffix) {
+		return "", false
+	}
+
+	socketPath, err := hex.DecodeString(strings.TrimSuffix(host, unixSocketHostSuffix))
+	if err != nil {
+		return "", false
+	}
+	return string(socketPath), true
+}
+
+// netnsPath returns the path of the network namespace. Names refer to the namespaces managed by `ip netns`.
+func netnsPath(netns string) string {
+	if strings.Contains(netns, "/") {
+		return netns
+	}
+	return "/var/run/netns/" + netns
+}
+
+// contextDialer dials connections, like net.Dialer.
+type contextDialer interface {
+	DialContext(ctx context.Context, network string, addr string) (net.Conn, error)
+}
+
+// routeCheckDialer fails right away, if there is no route to the address. Connecting a UDP socket only selects the
+// route and sends no packets, while a TCP connection may be attempted until the timeout, e.g. on Windows.
+type routeCheckDialer struct {
+	dialer *net.Dialer
+}
+
+func (d routeCheckDialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	udpDialer := *d.dialer
+	if localAddr, ok := d.dialer.LocalAddr.(*net.TCPAddr); ok {
+		udpDialer.LocalAddr = &net.UDPAddr{IP: localAddr.IP, Zone: localAddr.Zone}
+	}
+
+	conn, err := udpDialer.DialContext(ctx, strings.Replace(network, "tcp", "udp", 1), addr)
+	if err != nil && isNoRouteError(err) {
+		log.Printf("no route to '%s' 🚨: %s", addr, err)
+		return nil, err
+	}
+	// Other errors, e.g. of the name resolution, are reported by the TCP connection.
+	if err == nil {
+		conn.Close()
+	}
+
+	return d.dialer.DialContext(ctx, network, addr)
+}
+
+// dialCached resolves the host of addr using the dnsCache and dials the resulting addresses in turn.
+func dialCached(ctx context.Context, dialer contextDialer, cache *dnsCache, network string, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	ipAddrs, err := cache.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	lastErr := fmt.Errorf("the host '%s' has no address usable with the network '%s'", host, network)
+	for _, ipAddr := range ipAddrs {
+		version := networkIPVersion(network)
+		if (version == IPVersion4 && ipAddr.IP.To4() == nil) || (version == IPVersion6 && ipAddr.IP.To4() != nil) {
+			continue
+		}
+
+		var conn net.Conn
+		conn, lastErr = dialer.DialContext(ctx, network, net.JoinHostPort(ipAddr.String(), port))
+		if lastErr == nil {
+			return conn, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// localIP determines the local IP, which the operating system would use to reach the IP information provider over
+// the given (UDP) network. No packets are sent, as connecting a UDP socket only selects the route.
+func localIP(ctx context.Context, p *ProviderModel, network string) (netaddr.IP, error) {
+	providerURL := p.ipProviderURLs[0]
+	port := providerURL.Port()
+	if port == "" {
+		port = "80"
+		if providerURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(providerURL.Hostname(), port)
+
+	dialer := &net.Dialer{Timeout: p.timeout}
+	var conn net.Conn
+	var err error
+	if p.dnsCache != nil {
+		conn, err = dialCached(ctx, dialer, p.dnsCache, network, addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, network, addr)
+	}
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+	defer conn.Close()
+
+	udpAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return netaddr.IP{}, fmt.Errorf("unexpected local address '%s'", conn.LocalAddr())
+	}
+
+	ip, _ := netaddr.FromStdIP(udpAddr.IP)
+	log.Printf("got local IP 🏠: '%s' over '%s'", ip, network)
+	return ip, nil
+}
+
+// newRequestID generates a random (version 4) UUID, which is used to correlate the requests.
+func newRequestID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
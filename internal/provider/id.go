@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+const IDFormatSourceIP = "{source_ip}"
+const IDFormatIP = "{ip}"
+
+// IDDefaultSource replaces the source IP in the id when no source_ip is set.
+const IDDefaultSource = "default"
+
+// formatID renders the id of a data source, see the id_format provider option.
+func formatID(idFormat string, sourceIP string, ip string) string {
+	if idFormat == "" {
+		idFormat = DefaultIDFormat
+	}
+	if sourceIP == "" {
+		sourceIP = IDDefaultSource
+	}
+
+	return strings.NewReplacer(IDFormatSourceIP, sourceIP, IDFormatIP, ip).Replace(idFormat)
+}
+
+// idSource appends a digest of the set selectors to the source IP, so that data sources which only differ
+// in e.g. their ip_version or provider_url get different ids. Without selectors the source IP is returned as is.
+func idSource(sourceIP string, selectors map[string]string) string {
+	var set []string
+	for name, value := range selectors {
+		if value != "" {
+			set = append(set, name+"="+value)
+		}
+	}
+	if len(set) == 0 {
+		return sourceIP
+	}
+	if sourceIP == "" {
+		sourceIP = IDDefaultSource
+	}
+
+	sort.Strings(set)
+	digest := sha256.Sum256([]byte(strings.Join(set, "\n")))
+	return sourceIP + "#" + hex.EncodeToString(digest[:4])
+}
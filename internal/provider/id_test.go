@@ -0,0 +1,52 @@
+package provider
+
+import "testing"
+
+func TestFormatID(t *testing.T) {
+	tests := map[string]struct {
+		idFormat string
+		sourceIP string
+		ip       string
+		expected string
+	}{
+		"empty source": {DefaultIDFormat, "", "203.0.113.4", "default$203.0.113.4"},
+		"v4 source":    {DefaultIDFormat, "0.0.0.0", "203.0.113.4", "0.0.0.0$203.0.113.4"},
+		"v6 source":    {DefaultIDFormat, "::", "2001:db8::1", "::$2001:db8::1"},
+		"custom":       {"{ip}@{source_ip}", "::", "2001:db8::1", "2001:db8::1@::"},
+		"unconfigured": {"", "", "203.0.113.4", "default$203.0.113.4"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id := formatID(test.idFormat, test.sourceIP, test.ip)
+			if id != test.expected {
+				t.Errorf("expected id '%s', got '%s'", test.expected, id)
+			}
+		})
+	}
+}
+
+func TestIDSource(t *testing.T) {
+	if id := idSource("0.0.0.0", map[string]string{"prefer": "", "netns": ""}); id != "0.0.0.0" {
+		t.Errorf("expected the source IP without selectors, got '%s'", id)
+	}
+
+	ids := map[string]bool{}
+	for _, selectors := range []map[string]string{
+		{},
+		{"ip_version": "v4"},
+		{"ip_version": "v6"},
+		{"prefer": "v4"},
+		{"bind_device": "lo"},
+		{"vrf": "lo"},
+		{"netns": "blue"},
+		{"provider_url": "https://ipinfo.example"},
+		{"ip_version": "v4", "provider_url": "https://ipinfo.example"},
+	} {
+		id := idSource("", selectors)
+		if ids[id] {
+			t.Errorf("expected a unique id for %v, got '%s'", selectors, id)
+		}
+		ids[id] = true
+	}
+}
@@ -0,0 +1,272 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type InterfaceDataSource struct {
+	provider *ProviderModel
+}
+
+func NewInterfaceDataSource() datasource.DataSource {
+	return &InterfaceDataSource{}
+}
+
+func (d InterfaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_interface"
+}
+
+func (d InterfaceDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Lists the addresses of a local network interface, e.g. to pick the `source_ip` of `publicip_address`. No request is sent.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"name": {
+				MarkdownDescription: "The name of the interface, e.g. `eth0`.",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"index": {
+				MarkdownDescription: "The index of the interface.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+			"mtu": {
+				MarkdownDescription: "The maximum transmission unit of the interface.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+			"hardware_addr": {
+				MarkdownDescription: "The hardware (MAC) address of the interface. `null` if it has none, e.g. the loopback interface.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"flags": {
+				MarkdownDescription: "The flags of the interface, e.g. `up`, `broadcast`, `loopback`, `pointtopoint`, `multicast` or `running`.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"addresses": {
+				MarkdownDescription: "The addresses of the interface.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"ip": {
+						MarkdownDescription: "The IP.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"prefix_length": {
+						MarkdownDescription: "The length of the prefix of the network, e.g. `24`.",
+						Computed:            true,
+						Type:                types.Int64Type,
+					},
+					"cidr": {
+						MarkdownDescription: "The IP with its prefix length, e.g. `192.168.1.10/24`.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"ip_version": {
+						MarkdownDescription: fmt.Sprintf("Whether the IP is an IPv6 or IPv4. Expected values: '%s', '%s'", IPVersion6, IPVersion4),
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"scope": {
+						MarkdownDescription: fmt.Sprintf("The scope of the IP. Expected values: '%s', '%s', '%s', '%s', '%s', '%s', '%s'", IPScopeGlobal, IPScopePrivate, IPScopeUniqueLocal, IPScopeLinkLocal, IPScopeLoopback, IPScopeMulticast, IPScopeUnspecified),
+						Computed:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *InterfaceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type InterfaceDataSourceModel struct {
+	ID           types.String            `tfsdk:"id"`
+	Name         types.String            `tfsdk:"name"`
+	Index        types.Int64             `tfsdk:"index"`
+	MTU          types.Int64             `tfsdk:"mtu"`
+	HardwareAddr types.String            `tfsdk:"hardware_addr"`
+	Flags        []string                `tfsdk:"flags"`
+	Addresses    []InterfaceAddressModel `tfsdk:"addresses"`
+}
+
+type InterfaceAddressModel struct {
+	IP           types.String `tfsdk:"ip"`
+	PrefixLength types.Int64  `tfsdk:"prefix_length"`
+	CIDR         types.String `tfsdk:"cidr"`
+	IPVersion    types.String `tfsdk:"ip_version"`
+	Scope        types.String `tfsdk:"scope"`
+}
+
+func (d InterfaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data InterfaceDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	iface, err := net.InterfaceByName(data.Name.Value)
+	if err != nil {
+		log.Printf("Interface error 🚨: %s", err)
+		resp.Diagnostics.AddError("Unknown interface", fmt.Sprintf("The interface '%s' could not be found: %s", data.Name.Value, err))
+		return
+	}
+
+	prefixes, err := interfacePrefixes(iface)
+	if err != nil {
+		log.Printf("Interface error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error listing the addresses", fmt.Sprintf("The addresses of the interface '%s' could not be listed: %s", iface.Name, err))
+		return
+	}
+
+	data.ID = types.String{Value: iface.Name}
+	data.Index = types.Int64{Value: int64(iface.Index)}
+	data.MTU = types.Int64{Value: int64(iface.MTU)}
+	data.HardwareAddr = optionalString(iface.HardwareAddr.String())
+	data.Flags = []string{}
+	if iface.Flags != 0 {
+		data.Flags = strings.Split(iface.Flags.String(), "|")
+	}
+
+	data.Addresses = []InterfaceAddressModel{}
+	for _, prefix := range prefixes {
+		ip := prefix.IP()
+		data.Addresses = append(data.Addresses, InterfaceAddressModel{
+			IP:           types.String{Value: ip.String()},
+			PrefixLength: types.Int64{Value: int64(prefix.Bits())},
+			CIDR:         types.String{Value: prefix.String()},
+			IPVersion:    types.String{Value: ipVersion(ip)},
+			Scope:        types.String{Value: ipScope(ip)},
+		})
+	}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// interfacePrefixes returns the addresses of the interface with their prefix length.
+func interfacePrefixes(iface *net.Interface) ([]netaddr.IPPrefix, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var prefixes []netaddr.IPPrefix
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		prefix, ok := netaddr.FromStdIPNet(ipNet)
+		if !ok {
+			continue
+		}
+		prefixes = append(prefixes, netaddr.IPPrefixFrom(prefix.IP().Unmap(), prefix.Bits()))
+	}
+	return prefixes, nil
+}
+
+// interfaceSourceIP picks the address of the interface, which is used as source IP for the requested IP version.
+// Global addresses are preferred over private and loopback addresses. Link-local addresses are skipped,
+// as they can't reach an IP information provider on the internet.
+func interfaceSourceIP(name string, version string) (netaddr.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	prefixes, err := interfacePrefixes(iface)
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	rank := map[string]int{IPScopeGlobal: 1, IPScopePrivate: 2, IPScopeUniqueLocal: 2, IPScopeLoopback: 3}
+
+	best := netaddr.IP{}
+	for _, prefix := range prefixes {
+		ip := prefix.IP()
+		if rank[ipScope(ip)] == 0 || (version != "" && ipVersion(ip) != version) {
+			continue
+		}
+		if best.IsZero() || rank[ipScope(ip)] < rank[ipScope(best)] {
+			best = ip
+		}
+	}
+
+	if best.IsZero() {
+		if version != "" {
+			return netaddr.IP{}, fmt.Errorf("the interface '%s' has no usable %s address", iface.Name, version)
+		}
+		return netaddr.IP{}, fmt.Errorf("the interface '%s' has no usable address", iface.Name)
+	}
+
+	log.Printf("using '%s' of interface '%s' ✅", best, iface.Name)
+	return best, nil
+}
+
+// localIPInPrefix returns an address within the prefix, which is configured on any local network interface.
+func localIPInPrefix(prefix netaddr.IPPrefix) (netaddr.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	for _, iface := range ifaces {
+		iface := iface
+		prefixes, err := interfacePrefixes(&iface)
+		if err != nil {
+			log.Printf("Could not list the addresses of '%s' 🚨: %s", iface.Name, err)
+			continue
+		}
+
+		for _, ifacePrefix := range prefixes {
+			if prefix.Contains(ifacePrefix.IP()) {
+				log.Printf("using '%s' of interface '%s' ✅", ifacePrefix.IP(), iface.Name)
+				return ifacePrefix.IP(), nil
+			}
+		}
+	}
+	return netaddr.IP{}, fmt.Errorf("no local network interface has an address within '%s'", prefix)
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestInterfaceDataSourceLoopback(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loopback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	resp := testReadDataSource(t, NewInterfaceDataSource, nil, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, loopback),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data InterfaceDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if !data.HardwareAddr.Null {
+		t.Errorf("expected no hardware address: %+v", data)
+	}
+
+	hasLoopbackFlag := false
+	for _, flag := range data.Flags {
+		hasLoopbackFlag = hasLoopbackFlag || flag == "loopback"
+	}
+	if !hasLoopbackFlag {
+		t.Errorf("expected the loopback flag: %v", data.Flags)
+	}
+
+	for _, address := range data.Addresses {
+		if address.IP.Value == "127.0.0.1" {
+			if address.Scope.Value != IPScopeLoopback || address.IPVersion.Value != IPVersion4 || address.CIDR.Value != "127.0.0.1/"+address.PrefixLength.String() {
+				t.Errorf("unexpected address: %+v", address)
+			}
+			return
+		}
+	}
+	t.Errorf("expected 127.0.0.1: %+v", data.Addresses)
+}
+
+func TestInterfaceDataSourceUnknown(t *testing.T) {
+	resp := testReadDataSource(t, NewInterfaceDataSource, nil, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "does-not-exist0"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an unknown interface")
+	}
+}
@@ -5,32 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/big"
+	"net"
 	"net/http"
 	"net/url"
-	"path"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"golang.org/x/time/rate"
 	"inet.af/netaddr"
 )
 
 const IPVersion4 = "v4"
 const IPVersion6 = "v6"
 const IPUnknown = "unknown"
+const PreferAny = "any"
+
+const DefaultPrefixLengthIPv4 = 32
+const DefaultPrefixLengthIPv6 = 64
 
 type IPDataSource struct {
-	timeout       time.Duration
-	ipProviderURL *url.URL
-	rateLimiter   *rate.Limiter
-	version       string
+	provider *ProviderModel
+	// lookupHost resolves the A records of the Tor exit list.
+	lookupHost func(ctx context.Context, host string) ([]string, error)
 }
 
 func NewIpDataSource() datasource.DataSource {
-	return &IPDataSource{}
+	return &IPDataSource{lookupHost: net.DefaultResolver.LookupHost}
 }
 
 func (d IPDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -49,7 +53,8 @@ func (d IPDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnosti
 				Type:                types.StringType,
 			},
 			"ip_version": {
-				MarkdownDescription: fmt.Sprintf("Whether the returned IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				MarkdownDescription: fmt.Sprintf("Whether the returned IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'. Set it to '%s' or '%s' to request the IP of that version, i.e. to connect to the IP information provider over that IP stack only.", IPVersion6, IPVersion4, IPUnknown, IPVersion6, IPVersion4),
+				Optional:            true,
 				Computed:            true,
 				Type:                types.StringType,
 			},
@@ -68,6 +73,51 @@ func (d IPDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnosti
 				Computed:            true,
 				Type:                types.StringType,
 			},
+			"ip_decimal": {
+				MarkdownDescription: "The IP as a decimal number as returned by the IP information provider, e.g. `3405803780` for `203.0.113.4`. Useful for numeric comparisons and range checks.",
+				Computed:            true,
+				Type:                types.NumberType,
+			},
+			"ip_url_host": {
+				MarkdownDescription: "The IP in a form that can be used as host in a URL, i.e. IPv6 addresses are enclosed in brackets, e.g. `[2001:db8::1]`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"hostname": {
+				MarkdownDescription: "The hostname of the IP, i.e. its reverse DNS (PTR) name, as returned by the IP information provider. `null` if the IP has none.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"cidr": {
+				MarkdownDescription: fmt.Sprintf("The network of the IP with the `prefix_length` in CIDR notation, e.g. `203.0.113.4/32` or `2001:db8:1:2::/64`. Defaults to a /%d for IPv4 and a /%d for IPv6.", DefaultPrefixLengthIPv4, DefaultPrefixLengthIPv6),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"prefix_length": {
+				MarkdownDescription: "The prefix length of the `cidr`, e.g. `24` to get the containing /24 of an IPv4.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"scope": {
+				MarkdownDescription: fmt.Sprintf("The scope of the IP. Expected values: '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s'", IPScopeGlobal, IPScopePrivate, IPScopeUniqueLocal, IPScopeLinkLocal, IPScopeLoopback, IPScopeMulticast, IPScopeUnspecified, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"is_private": {
+				MarkdownDescription: "`true` if the IP is private, i.e. in RFC 1918 (IPv4) or RFC 4193 (IPv6). An IP information provider, which returns a private IP, is most likely misconfigured.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_cgnat": {
+				MarkdownDescription: "`true` if the IP is in the shared address space of carrier-grade NAT, i.e. `100.64.0.0/10` (RFC 6598).",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_bogon": {
+				MarkdownDescription: "`true` if the IP is reserved or not allocated, e.g. private, CGNAT or documentation ranges, i.e. if it is not a valid public IP.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
 			"asn_id": {
 				MarkdownDescription: "The ASN as returned by the IP information provider.",
 				Computed:            true,
@@ -78,14 +128,252 @@ func (d IPDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnosti
 				Computed:            true,
 				Type:                types.StringType,
 			},
+			"asn_eu": {
+				MarkdownDescription: "`true` if the ASN is registered in a member state of the European Union, according to its RDAP registration. Only looked up if `lookup_asn_eu` is set, `null` otherwise or if the registration states no country.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"lookup_asn_eu": {
+				MarkdownDescription: "Look up the registration of the ASN with RDAP to determine `asn_eu`. This requires an additional request to the RDAP service, see `rdap_url` on the provider. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"is_tor_exit": {
+				MarkdownDescription: "`true` if the IP is a Tor exit node, according to the Tor exit list of the Tor Project (TorDNSEL). Only looked up if `lookup_tor_exit` is set, `null` otherwise or for IPv6, which the list does not cover.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"lookup_tor_exit": {
+				MarkdownDescription: fmt.Sprintf("Look up the IP in the Tor exit list to determine `is_tor_exit`. This requires an additional DNS query to `%s`. Defaults to `false`.", torDNSELZone),
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"is_vpn": {
+				MarkdownDescription: "`true` if the IP is the exit of a VPN, according to the `privacy_service` of the provider. `null` if no `privacy_service` is set or it does not provide this flag.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_proxy": {
+				MarkdownDescription: "`true` if the IP is an open proxy, according to the `privacy_service` of the provider. `null` if no `privacy_service` is set or it does not provide this flag.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_hosting": {
+				MarkdownDescription: "`true` if the IP belongs to a hosting provider or data center, according to the `privacy_service` of the provider. `null` if no `privacy_service` is set or it does not provide this flag.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"country": {
+				MarkdownDescription: "The country as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country_iso": {
+				MarkdownDescription: "The ISO code of the country as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country_eu": {
+				MarkdownDescription: "`true` if the country is a member state of the European Union, as returned by the IP information provider. `null` if the IP information provider does not tell.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"region_name": {
+				MarkdownDescription: "The name of the region as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region_code": {
+				MarkdownDescription: "The code of the region as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"city": {
+				MarkdownDescription: "The city as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"zip_code": {
+				MarkdownDescription: "The ZIP code as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"time_zone": {
+				MarkdownDescription: "The time zone as returned by the IP information provider, e.g. `Europe/Zurich`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"latitude": {
+				MarkdownDescription: "The latitude of the IP as returned by the IP information provider. See `latitude_exact` for the unrounded value.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"latitude_exact": {
+				MarkdownDescription: "The latitude of the IP exactly as returned by the IP information provider, i.e. without any rounding.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"longitude": {
+				MarkdownDescription: "The longitude of the IP as returned by the IP information provider. See `longitude_exact` for the unrounded value.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"longitude_exact": {
+				MarkdownDescription: "The longitude of the IP exactly as returned by the IP information provider, i.e. without any rounding.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"request_id": {
+				MarkdownDescription: "The unique id that was sent to the IP information provider in the header configured by `request_id_header`. `null` if no such header is configured.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"provider_url_used": {
+				MarkdownDescription: "The URL of the IP information provider, which provided the information.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"checked_at": {
+				MarkdownDescription: "The time of the lookup in RFC 3339 format, e.g. `2006-01-02T15:04:05Z`. It is not part of the `id`. `null` if `suppress_checked_at` is set on the provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"latency_ms": {
+				MarkdownDescription: "The round-trip time in milliseconds to the IP information provider, measured by the TCP handshake. Unlike `response_time_ms`, this excludes the time the IP information provider takes to process the request, so it is a rough signal of the egress latency. If an established connection is reused, there is no handshake and the time from sending the request to the first byte of the response is used instead, which includes the processing time.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+			"response_time_ms": {
+				MarkdownDescription: "The time in milliseconds it took the IP information provider to respond.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+			"raw_json": {
+				MarkdownDescription: "The entire response of the IP information provider as JSON. Use `jsondecode()` to access fields, which are not available as attributes.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"all": {
+				MarkdownDescription: "All scalar attributes of this data source collapsed into a single map of strings. Attributes without value and `raw_json` are omitted.",
+				Computed:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"require_geo": {
+				MarkdownDescription: "Fail if the response of the IP information provider contains no geolocation, i.e. neither a country nor a city. Empty values are accepted, as long as they are present. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"prefer": {
+				MarkdownDescription: fmt.Sprintf("The preferred IP stack to connect to the IP information provider: '%s', '%s' or '%s'. The other IP stack is used, if the request over the preferred one fails transiently or finds no route, which is reported as warning. See `used_stack`. Ignored if `source_ip` or `ip_version` is set. Defaults to '%s', i.e. the choice of the operating system.", IPVersion6, IPVersion4, PreferAny, PreferAny),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"used_stack": {
+				MarkdownDescription: fmt.Sprintf("The IP stack, which was used to connect to the IP information provider. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"bound_source_ip": {
+				MarkdownDescription: "The local IP, which was actually used to connect to the IP information provider. Unlike `source_ip`, this is always a concrete address, e.g. the global IPv6 address chosen for `::`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"is_nat": {
+				MarkdownDescription: "`true` if the returned IP differs from the `bound_source_ip`, i.e. if the connection to the IP information provider is translated by a NAT. `null` if the local IP is unknown, e.g. when connecting through a proxy.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
 			"source_ip": {
 				MarkdownDescription: `Set the source IP address that is used to make the request to the IP information provider.
 The address must be configured on a local network interface and that interface will be used.
+Instead of an IP, a hostname can be given, which is resolved locally.
+If it resolves to both IP stacks, the address is chosen according to ` + "`ip_version`" + ` or ` + "`prefer`" + `.
+Link-local IPv6 addresses require the zone of their interface, e.g. ` + "`fe80::1%eth0`" + `.
 Leave empty or ` + "`null`" + ` for default interface and IP stack.
 ` + "Set to `::` to get your public IPv6 address and `0.0.0.0` to get your IPv4 address.",
 				Optional: true,
 				Type:     types.StringType,
 			},
+			"strict_source": {
+				MarkdownDescription: "Verify that the `source_ip` is configured on a local network interface before connecting to the IP information provider, " +
+					"to fail with a precise error instead of a generic connection error. The unspecified addresses `0.0.0.0` and `::` are always accepted. Defaults to `false`.",
+				Optional: true,
+				Type:     types.BoolType,
+			},
+			"source_interface": {
+				MarkdownDescription: "Set the name of the local network interface, e.g. `wg0`, whose address is used as source IP. " +
+					"The address is chosen according to `ip_version` or `prefer`, global addresses are preferred over private ones. " +
+					"Can't be combined with `source_ip` or `source_cidr`.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+			"source_cidr": {
+				MarkdownDescription: "Use any address, which is configured on a local network interface and lies within the CIDR, e.g. `192.0.2.0/24`, as source IP. " +
+					"Useful if the hosts have different static addresses in the same subnet. " +
+					"Can't be combined with `source_ip` or `source_interface`.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+			"bind_device": {
+				MarkdownDescription: "Bind the connection to the IP information provider to the network device, e.g. `wg0`, using `SO_BINDTODEVICE`. " +
+					"Unlike `source_ip`, this guarantees the traffic leaves through that device, even if policy routing would choose another one. " +
+					"Only supported on Linux.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+			"vrf": {
+				MarkdownDescription: "Make the request to the IP information provider within a Linux VRF, e.g. `mgmt`. " +
+					"The connection is bound to the VRF device using `SO_BINDTODEVICE`, so the routing table of the VRF applies. " +
+					"Can't be combined with `bind_device`. Only supported on Linux.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+			"provider_url": {
+				MarkdownDescription: "URL to an ifconfig.co-compatible IP information provider, which is used instead of the `provider_url`, `provider_urls` or `consensus` of the provider.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"rate_limit_rate": {
+				MarkdownDescription: "Overrides the `rate_limit_rate` of the provider for this data source, e.g. to query a self-hosted IP information provider faster. " +
+					"Data sources with the same rate limit share it. Defaults to the `rate_limit_rate` of the provider.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+			"rate_limit_burst": {
+				MarkdownDescription: "Overrides the `rate_limit_burst` of the provider for this data source. Defaults to the `rate_limit_burst` of the provider.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"headers": {
+				MarkdownDescription: "Additional headers, e.g. for authentication, which are sent along with the request to the IP information provider. They take precedence over the `headers` of the provider and the headers it sets, like the User-Agent.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"netns": {
+				MarkdownDescription: "Make the request to the IP information provider from within a Linux network namespace, " +
+					"to discover the public IP of a container or VRF-like environment. " +
+					"Either the name of a namespace managed by `ip netns`, e.g. `blue`, or a path, e.g. `/proc/1234/ns/net`. " +
+					"The hostname of the IP information provider is resolved outside the namespace. " +
+					"Entering a namespace usually requires root privileges. Only supported on Linux.",
+				Optional: true,
+				Type:     types.StringType,
+			},
+		},
+
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": {
+				MarkdownDescription: "Timeouts of this data source, which take precedence over the timeouts of the provider.",
+				NestingMode:         tfsdk.BlockNestingModeSingle,
+				Attributes: map[string]tfsdk.Attribute{
+					"read": {
+						MarkdownDescription: "Timeout of reading this data source, e.g. `30s`. " +
+							"Replaces the `timeout` and `request_timeout` of the provider for each request and the `total_timeout` of the provider for the whole read, including retries.",
+						Optional: true,
+						Type:     types.StringType,
+					},
+				},
+			},
 		},
 	}, nil
 }
@@ -106,21 +394,73 @@ func (d *IPDataSource) Configure(_ context.Context, req datasource.ConfigureRequ
 		return
 	}
 
-	d.timeout = p.timeout
-	d.ipProviderURL = p.ipProviderURL
-	d.rateLimiter = p.rateLimiter
-	d.version = p.version
+	d.provider = p
 }
 
 type IpDataSourceModel struct {
-	ID        types.String `tfsdk:"id"`
-	IPVersion types.String `tfsdk:"ip_version"`
-	IsIPv6    types.Bool   `tfsdk:"is_ipv6"`
-	IsIPv4    types.Bool   `tfsdk:"is_ipv4"`
-	IP        types.String `tfsdk:"ip"`
-	ASNID     types.String `tfsdk:"asn_id"`
-	ASNOrg    types.String `tfsdk:"asn_org"`
-	SourceIP  types.String `tfsdk:"source_ip"`
+	ID              types.String   `tfsdk:"id"`
+	IPVersion       types.String   `tfsdk:"ip_version"`
+	IsIPv6          types.Bool     `tfsdk:"is_ipv6"`
+	IsIPv4          types.Bool     `tfsdk:"is_ipv4"`
+	IP              types.String   `tfsdk:"ip"`
+	IPDecimal       types.Number   `tfsdk:"ip_decimal"`
+	IPURLHost       types.String   `tfsdk:"ip_url_host"`
+	CIDR            types.String   `tfsdk:"cidr"`
+	PrefixLength    types.Int64    `tfsdk:"prefix_length"`
+	Hostname        types.String   `tfsdk:"hostname"`
+	Scope           types.String   `tfsdk:"scope"`
+	IsPrivate       types.Bool     `tfsdk:"is_private"`
+	IsCGNAT         types.Bool     `tfsdk:"is_cgnat"`
+	IsBogon         types.Bool     `tfsdk:"is_bogon"`
+	ASNID           types.String   `tfsdk:"asn_id"`
+	ASNOrg          types.String   `tfsdk:"asn_org"`
+	ASNEU           types.Bool     `tfsdk:"asn_eu"`
+	LookupASNEU     types.Bool     `tfsdk:"lookup_asn_eu"`
+	IsTorExit       types.Bool     `tfsdk:"is_tor_exit"`
+	LookupTorExit   types.Bool     `tfsdk:"lookup_tor_exit"`
+	IsVPN           types.Bool     `tfsdk:"is_vpn"`
+	IsProxy         types.Bool     `tfsdk:"is_proxy"`
+	IsHosting       types.Bool     `tfsdk:"is_hosting"`
+	Country         types.String   `tfsdk:"country"`
+	CountryISO      types.String   `tfsdk:"country_iso"`
+	CountryEU       types.Bool     `tfsdk:"country_eu"`
+	RegionName      types.String   `tfsdk:"region_name"`
+	RegionCode      types.String   `tfsdk:"region_code"`
+	City            types.String   `tfsdk:"city"`
+	ZIPCode         types.String   `tfsdk:"zip_code"`
+	TimeZone        types.String   `tfsdk:"time_zone"`
+	Latitude        types.Float64  `tfsdk:"latitude"`
+	LatitudeExact   types.String   `tfsdk:"latitude_exact"`
+	Longitude       types.Float64  `tfsdk:"longitude"`
+	LongitudeExact  types.String   `tfsdk:"longitude_exact"`
+	RequestID       types.String   `tfsdk:"request_id"`
+	ProviderURLUsed types.String   `tfsdk:"provider_url_used"`
+	ResponseTimeMS  types.Int64    `tfsdk:"response_time_ms"`
+	LatencyMS       types.Int64    `tfsdk:"latency_ms"`
+	CheckedAt       types.String   `tfsdk:"checked_at"`
+	RawJSON         types.String   `tfsdk:"raw_json"`
+	All             types.Map      `tfsdk:"all"`
+	RequireGeo      types.Bool     `tfsdk:"require_geo"`
+	SourceIP        types.String   `tfsdk:"source_ip"`
+	StrictSource    types.Bool     `tfsdk:"strict_source"`
+	SourceInterface types.String   `tfsdk:"source_interface"`
+	SourceCIDR      types.String   `tfsdk:"source_cidr"`
+	BindDevice      types.String   `tfsdk:"bind_device"`
+	VRF             types.String   `tfsdk:"vrf"`
+	Netns           types.String   `tfsdk:"netns"`
+	ProviderURL     types.String   `tfsdk:"provider_url"`
+	RateLimitRate   types.String   `tfsdk:"rate_limit_rate"`
+	RateLimitBurst  types.Int64    `tfsdk:"rate_limit_burst"`
+	Headers         types.Map      `tfsdk:"headers"`
+	Timeouts        *TimeoutsModel `tfsdk:"timeouts"`
+	BoundSourceIP   types.String   `tfsdk:"bound_source_ip"`
+	IsNAT           types.Bool     `tfsdk:"is_nat"`
+	Prefer          types.String   `tfsdk:"prefer"`
+	UsedStack       types.String   `tfsdk:"used_stack"`
+}
+
+type TimeoutsModel struct {
+	Read types.String `tfsdk:"read"`
 }
 
 func (d IPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -137,29 +477,184 @@ func (d IPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp
 
 	log.Printf("got to client ✅")
 
-	client := &http.Client{
-		Timeout: d.timeout,
-	}
-
 	if data.SourceIP.Null {
 		data.SourceIP = types.String{Value: ""}
 	}
 
+	sources := 0
+	for _, source := range []bool{data.SourceIP.Value != "", !data.SourceInterface.Null, !data.SourceCIDR.Null} {
+		if source {
+			sources++
+		}
+	}
+	if sources > 1 {
+		resp.Diagnostics.AddError("Conflicting source attributes", "Only one of source_ip, source_interface and source_cidr can be set.")
+		return
+	}
+
+	bindDevice := ""
+	if !data.BindDevice.Null {
+		if !bindDeviceSupported {
+			resp.Diagnostics.AddError("Unsupported bind_device", fmt.Sprintf("The bind_device '%s' can't be used, because binding to a device is only supported on Linux.", data.BindDevice.Value))
+			return
+		}
+		bindDevice = data.BindDevice.Value
+	}
+
+	if !data.VRF.Null {
+		if !data.BindDevice.Null {
+			resp.Diagnostics.AddError("Conflicting bind_device and vrf", "Only one of bind_device and vrf can be set.")
+			return
+		}
+		if !bindDeviceSupported {
+			resp.Diagnostics.AddError("Unsupported vrf", fmt.Sprintf("The vrf '%s' can't be used, because VRFs are only supported on Linux.", data.VRF.Value))
+			return
+		}
+		if _, err := net.InterfaceByName(data.VRF.Value); err != nil {
+			log.Printf("VRF error 🚨: %s", err)
+			resp.Diagnostics.AddError("Unknown vrf", fmt.Sprintf("The VRF device '%s' could not be found: %s", data.VRF.Value, err))
+			return
+		}
+		bindDevice = data.VRF.Value
+	}
+
+	netns := ""
+	if !data.Netns.Null {
+		if !netnsSupported {
+			resp.Diagnostics.AddError("Unsupported netns", fmt.Sprintf("The netns '%s' can't be used, because network namespaces are only supported on Linux.", data.Netns.Value))
+			return
+		}
+		netns = netnsPath(data.Netns.Value)
+	}
+
+	p := d.provider
+	if data.Timeouts != nil && !data.Timeouts.Read.Null {
+		readTimeout, err := time.ParseDuration(data.Timeouts.Read.Value)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to parse the read timeout", fmt.Sprintf("The timeouts.read value '%s' can't be parsed: %s", data.Timeouts.Read.Value, err))
+			return
+		}
+
+		// The read timeout replaces the total_timeout instead of being bounded by it.
+		p = p.withTimeout(readTimeout)
+		var cancel context.CancelFunc
+		ctx, cancel = withReadTimeout(ctx, "timeouts.read", readTimeout)
+		defer cancel()
+	} else {
+		var cancel context.CancelFunc
+		ctx, cancel = withTotalTimeout(ctx, p)
+		defer cancel()
+	}
+
+	if !data.RateLimitRate.Null || !data.RateLimitBurst.Null {
+		rateLimitRate := p.rateLimitRate
+		if !data.RateLimitRate.Null {
+			var err error
+			rateLimitRate, err = time.ParseDuration(data.RateLimitRate.Value)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to parse the rate_limit_rate", fmt.Sprintf("The rate_limit_rate value '%s' can't be parsed: %s", data.RateLimitRate.Value, err))
+				return
+			}
+		}
+
+		rateLimitBurst := p.rateLimitBurst
+		if !data.RateLimitBurst.Null {
+			rateLimitBurst, diags = parseRateLimitBurst(data.RateLimitBurst.Value)
+			resp.Diagnostics.Append(diags...)
+			if diags.HasError() {
+				return
+			}
+		}
+
+		p = p.withRateLimit(rateLimitRate, rateLimitBurst)
+	}
+
+	if !data.Headers.Null {
+		headers := map[string]string{}
+		diags = data.Headers.ElementsAs(ctx, &headers, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		p = p.withHeaders(headers)
+	}
+
+	var providerURLs []*url.URL
+	if !data.ProviderURL.Null {
+		providerURL, err := url.Parse(data.ProviderURL.Value)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to parse the provider_url", fmt.Sprintf("The provider_url value '%s' can't be parsed: %s", data.ProviderURL.Value, err))
+			return
+		}
+		providerURLs = []*url.URL{providerURL}
+	}
+
 	sourceIP := netaddr.IP{}
-	if data.SourceIP.Value != "" {
+	// sourceAttribute is the attribute, which determines the sourceIP, if any.
+	sourceAttribute, sourceValue := "source_ip", data.SourceIP.Value
+	if !data.SourceInterface.Null {
+		sourceAttribute, sourceValue = "source_interface", data.SourceInterface.Value
+
+		// The address is chosen for the requested IP version, otherwise for the preferred one.
+		version := ""
+		if !data.IPVersion.Null {
+			version = data.IPVersion.Value
+		} else if !data.Prefer.Null && data.Prefer.Value != PreferAny {
+			version = data.Prefer.Value
+		}
+		if version != IPVersion4 && version != IPVersion6 {
+			version = ""
+		}
+
+		var err error
+		sourceIP, err = interfaceSourceIP(data.SourceInterface.Value, version)
+		if err != nil {
+			log.Printf("Could not use interface '%s' 🚨: %s", data.SourceInterface.Value, err)
+			resp.Diagnostics.AddError("Invalid interface", fmt.Sprintf("The source_interface '%s' could not be used: %s", data.SourceInterface.Value, err))
+			return
+		}
+	} else if !data.SourceCIDR.Null {
+		sourceAttribute, sourceValue = "source_cidr", data.SourceCIDR.Value
+		prefix, err := netaddr.ParseIPPrefix(data.SourceCIDR.Value)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid source_cidr", fmt.Sprintf("The source_cidr '%s' can't be parsed: %s", data.SourceCIDR.Value, err))
+			return
+		}
+
+		sourceIP, err = localIPInPrefix(prefix.Masked())
+		if err != nil {
+			log.Printf("Could not use CIDR '%s' 🚨: %s", data.SourceCIDR.Value, err)
+			resp.Diagnostics.AddError("Invalid source_cidr", fmt.Sprintf("The source_cidr '%s' could not be used: %s", data.SourceCIDR.Value, err))
+			return
+		}
+	} else if data.SourceIP.Value != "" {
 		sourceIPStr := data.SourceIP.Value
 
 		var err error
-		sourceIP, err = netaddr.ParseIP(sourceIPStr)
-		if err != nil || !sourceIP.IsValid() {
+		// A hostname resolves to an address of the requested or preferred IP stack, if it has one.
+		preferredVersion := data.IPVersion.Value
+		if preferredVersion == "" && data.Prefer.Value != PreferAny {
+			preferredVersion = data.Prefer.Value
+		}
+		sourceIP, err = resolveIP(ctx, sourceIPStr, preferredVersion)
+		if err != nil {
 			log.Printf("Could not parse IP '%s' 🚨: %s", sourceIPStr, err)
-			resp.Diagnostics.AddError("Invalid IP", fmt.Sprintf("The IP '%s' could not be parsed as valid IP: %s", sourceIPStr, err))
+			resp.Diagnostics.AddError("Invalid IP", withRemediation(errorClassInvalidSourceIP, fmt.Sprintf("The source_ip '%s' could not be used: %s", sourceIPStr, err)))
 			return
 		}
+
+		if data.StrictSource.Value && !sourceIP.IsUnspecified() {
+			_, err = localIPInPrefix(netaddr.IPPrefixFrom(sourceIP.WithZone(""), sourceIP.BitLen()))
+			if err != nil {
+				log.Printf("Source IP '%s' not configured 🚨: %s", sourceIP, err)
+				resp.Diagnostics.AddError("Source IP not configured", withRemediation(errorClassInvalidSourceIP, fmt.Sprintf("The address '%s' of the source_ip '%s' is not present on any local network interface.", sourceIP.WithZone(""), sourceIPStr)))
+				return
+			}
+		}
 	}
 
 	network := "tcp"
-	if data.SourceIP.Value != "" {
+	if !sourceIP.IsZero() {
 		if sourceIP.Is6() {
 			network = "tcp6"
 		} else if sourceIP.Is4() {
@@ -167,94 +662,191 @@ func (d IPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp
 		}
 	}
 
-	forceNetwork(client, network, sourceIP)
+	requestedVersion := ""
+	if !data.IPVersion.Null && !data.IPVersion.Unknown {
+		requestedVersion = data.IPVersion.Value
+		if requestedVersion != IPVersion4 && requestedVersion != IPVersion6 {
+			resp.Diagnostics.AddError("Invalid ip_version", fmt.Sprintf("The ip_version '%s' must be either '%s' or '%s'.", requestedVersion, IPVersion4, IPVersion6))
+			return
+		}
 
-	baseURL := d.ipProviderURL
-	requestURL := url.URL{
-		Scheme:     baseURL.Scheme,
-		Opaque:     baseURL.Opaque,
-		User:       baseURL.User,
-		Host:       baseURL.Host,
-		Path:       path.Join(baseURL.Path, "json"),
-		ForceQuery: baseURL.ForceQuery,
-		RawQuery:   baseURL.RawQuery,
-		Fragment:   baseURL.Fragment,
+		if network != "tcp" && networkIPVersion(network) != requestedVersion {
+			resp.Diagnostics.AddError("Conflicting ip_version and "+sourceAttribute, fmt.Sprintf("The ip_version '%s' can't be requested from the %s '%s'.", requestedVersion, sourceAttribute, sourceValue))
+			return
+		}
+		network = "tcp" + strings.TrimPrefix(requestedVersion, "v")
 	}
-	requestURLstr := requestURL.String()
 
-	log.Printf("got to prepare request ✅: %s", requestURLstr)
+	networks := []string{network}
+	if !data.Prefer.Null && data.Prefer.Value != PreferAny {
+		if data.Prefer.Value != IPVersion4 && data.Prefer.Value != IPVersion6 {
+			resp.Diagnostics.AddError("Invalid prefer", fmt.Sprintf("The prefer value '%s' must be one of '%s', '%s' or '%s'.", data.Prefer.Value, IPVersion6, IPVersion4, PreferAny))
+			return
+		}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURLstr, nil)
-	if err != nil {
-		log.Printf("HTTP Client Creation Error 🚨: %s", err)
-		resp.Diagnostics.AddError("Error preparing the HTTP request", fmt.Sprintf("There was an error when preparing the HTTP client with the url '%s': %s", requestURLstr, err))
-		return
+		// The preference only applies if the IP stack is not given by source_ip or ip_version already.
+		if network == "tcp" {
+			networks = []string{"tcp6", "tcp4"}
+			if data.Prefer.Value == IPVersion4 {
+				networks = []string{"tcp4", "tcp6"}
+			}
+		}
 	}
 
-	userAgent := fmt.Sprintf("%s (%s)", UserAgent, d.version)
-	httpReq.Header.Set("User-Agent", userAgent)
-
-	log.Printf("got to send request ✅: %s", userAgent)
-
-	if !d.rateLimiter.Allow() {
-		log.Printf("the rate limit may be triggered ⏳")
-	}
+	var client, lookupClient *http.Client
+	var result *ipFetchResult
+	var hint retryHint
+	for i, network := range networks {
+		client = newBoundHTTPClient(p, network, sourceIP, bindDevice, netns)
+		lookupClient = newBoundLookupHTTPClient(p, network, sourceIP, bindDevice, netns)
+		result, hint, diags = fetchIPFromURLs(ctx, p, client, providerURLs, nil)
+		// Only connectivity failures are specific to the IP stack, e.g. not a response, which can't be parsed.
+		if !diags.HasError() || i == len(networks)-1 || ctx.Err() != nil || !hint.connectivity() {
+			break
+		}
 
-	timeoutCtx, cancelFunc := context.WithTimeout(ctx, d.timeout)
-	defer cancelFunc()
-	err = d.rateLimiter.Wait(timeoutCtx)
-	if err != nil {
-		log.Printf("Rate limiter error 🚨: %s", err)
-		resp.Diagnostics.AddError("Error waiting for rate limit", fmt.Sprintf("There was an error while awaiting a slot from the rate limiter: %s", err))
+		log.Printf("falling back from '%s' to '%s' 🔀: %v", network, networks[i+1], diags)
+		var reasons []string
+		for _, e := range diags.Errors() {
+			reasons = append(reasons, e.Detail())
+		}
+		resp.Diagnostics.AddWarning("Fell back to another IP stack", fmt.Sprintf("The request over '%s' failed, so it was retried over '%s':\n%s", networkIPVersion(network), networkIPVersion(networks[i+1]), strings.Join(reasons, "\n")))
 	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		log.Printf("HTTP client error 🚨: %s", err)
-		resp.Diagnostics.AddError("Error fetching information from the IP information provider", fmt.Sprintf("There was an error when contacting '%s': %s", requestURLstr, err))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer httpResp.Body.Close()
 
-	log.Printf("got to response ✅")
+	respData := result.respData
+	ip := result.ip
 
-	if httpResp.StatusCode != http.StatusOK {
-		log.Printf("HTTP Request Error 🚨: %d %s", httpResp.StatusCode, httpResp.Status)
-		resp.Diagnostics.AddError("Error in response from the IP information provider", fmt.Sprintf("The IP information provider responded with the status code %d '%s'", httpResp.StatusCode, httpResp.Status))
+	if data.RequireGeo.Value && !respData.has("country") && !respData.has("city") {
+		log.Printf("Geolocation missing 🚨: %+v", respData)
+		resp.Diagnostics.AddError("Missing geolocation", fmt.Sprintf("The response from '%s' contains no geolocation, but require_geo is set.", result.requestURL))
 		return
 	}
 
-	log.Printf("got to reading ✅")
+	data.ASNEU = types.Bool{Null: true}
+	if data.LookupASNEU.Value {
+		country, err := lookupASNCountry(ctx, p, lookupClient, respData.ASN)
+		if err != nil {
+			log.Printf("ASN lookup error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error looking up the ASN", fmt.Sprintf("The registration of the ASN '%s' could not be looked up, but lookup_asn_eu is set: %s", respData.ASN, err))
+			return
+		}
+		if country != "" {
+			data.ASNEU = types.Bool{Value: isEUCountry(country)}
+		}
+	}
+
+	data.IsTorExit = types.Bool{Null: true}
+	if data.LookupTorExit.Value && ip.Unmap().Is4() {
+		torExit, err := lookupTorExit(ctx, d.lookupHost, ip)
+		if err != nil {
+			log.Printf("Tor exit list error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error looking up the Tor exit list", fmt.Sprintf("The IP '%s' could not be looked up in the Tor exit list, but lookup_tor_exit is set: %s", ip, err))
+			return
+		}
+		data.IsTorExit = types.Bool{Value: torExit}
+	}
 
-	reader := httpResp.Body
+	data.IsVPN = types.Bool{Null: true}
+	data.IsProxy = types.Bool{Null: true}
+	data.IsHosting = types.Bool{Null: true}
+	if d.provider.privacyService != "" {
+		rep, err := lookupReputation(ctx, p, lookupClient, d.provider.privacyService, d.provider.privacyEndpoint, d.provider.PrivacyAPIKey.Value, ip)
+		if err != nil {
+			log.Printf("Privacy detection error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error looking up the privacy detection", fmt.Sprintf("The IP '%s' could not be looked up with the privacy_service '%s': %s", ip, d.provider.privacyService, err))
+			return
+		}
+		data.IsVPN = optionalBool(rep.vpn)
+		data.IsProxy = optionalBool(rep.proxy)
+		data.IsHosting = optionalBool(rep.hosting)
+	}
 
-	respData := new(IPResponse)
-	err = json.NewDecoder(reader).Decode(respData)
+	cidr, err := ipCIDR(ip, data.PrefixLength)
 	if err != nil {
-		log.Printf("JSON decode error 🚨: %s", err)
-		resp.Diagnostics.AddError("Error parsing the response from the IP information provider", fmt.Sprintf("There was an error when parsing the response from the IP information provider: %s", err))
+		log.Printf("CIDR error 🚨: %s", err)
+		resp.Diagnostics.AddError("Invalid prefix_length", fmt.Sprintf("The cidr could not be determined: %s", err))
 		return
 	}
 
-	log.Printf("got to parse ip response ✅: %+v", respData)
-
-	ip, err := netaddr.ParseIP(respData.IP)
-	if err != nil {
-		log.Printf("IP '%s' decode error 🚨: %s", respData.IP, err)
-		resp.Diagnostics.AddError("Error parsing the IP from the IP information provider", fmt.Sprintf("There was an error when parsing the IP '%s' of the response from the IP information provider: %s", respData.IP, err))
+	if requestedVersion != "" && ipVersion(ip) != requestedVersion {
+		log.Printf("IP version mismatch 🚨: %s", ip)
+		resp.Diagnostics.AddError("Unexpected IP version", fmt.Sprintf("The ip_version '%s' was requested, but the IP information provider returned '%s'.", requestedVersion, ip))
 		return
 	}
 
 	log.Printf("got to apply ✅: %+v", respData)
 
-	data.ID = types.String{Value: fmt.Sprintf("%s$%s", data.SourceIP.Value, respData.IP)}
+	// The address chosen for source_interface or source_cidr keeps the ids of such data sources unique.
+	source := data.SourceIP.Value
+	if sourceAttribute != "source_ip" {
+		source = sourceIP.String()
+	}
+	source = idSource(source, map[string]string{
+		"ip_version":   data.IPVersion.Value,
+		"prefer":       data.Prefer.Value,
+		"bind_device":  data.BindDevice.Value,
+		"vrf":          data.VRF.Value,
+		"netns":        data.Netns.Value,
+		"provider_url": data.ProviderURL.Value,
+	})
+	data.ID = types.String{Value: formatID(d.provider.idFormat, source, respData.IP)}
 	data.IP = types.String{Value: ip.String()}
 	data.IPVersion = types.String{Value: ipVersion(ip)}
 	data.IsIPv6 = types.Bool{Value: ip.Is6()}
 	data.IsIPv4 = types.Bool{Value: ip.Is4()}
 	data.IP = types.String{Value: ip.String()}
+	data.IPURLHost = types.String{Value: ipURLHost(ip)}
+	data.IPDecimal = decimalValue(respData.IPDecimal)
+	data.Scope = types.String{Value: ipScope(ip)}
+	data.IsPrivate = types.Bool{Value: ipIsPrivate(ip)}
+	data.IsCGNAT = types.Bool{Value: ipIsCGNAT(ip)}
+	data.IsBogon = types.Bool{Value: ipIsBogon(ip)}
+	data.CIDR = types.String{Value: cidr.String()}
+	data.Hostname = types.String{Null: true}
+	if respData.Hostname != "" {
+		data.Hostname = types.String{Value: respData.Hostname}
+	}
 	data.ASNID = types.String{Value: respData.ASN}
 	data.ASNOrg = types.String{Value: respData.ASNOrg}
+	data.Country = types.String{Value: respData.Country}
+	data.CountryISO = types.String{Value: respData.CountryISO}
+	data.CountryEU = types.Bool{Null: true}
+	if respData.has("country_eu") {
+		data.CountryEU = types.Bool{Value: respData.CountryEU}
+	}
+	data.RegionName = types.String{Value: respData.RegionName}
+	data.RegionCode = types.String{Value: respData.RegionCode}
+	data.City = types.String{Value: respData.City}
+	data.ZIPCode = types.String{Value: respData.ZIPCode}
+	data.TimeZone = types.String{Value: respData.TimeZone}
+	data.Latitude, data.LatitudeExact = coordinateValues(respData.Latitude)
+	data.Longitude, data.LongitudeExact = coordinateValues(respData.Longitude)
+	data.ProviderURLUsed = types.String{Value: result.providerURL}
+	data.BoundSourceIP = types.String{Null: true}
+	data.UsedStack = types.String{Value: IPUnknown}
+	if result.localIP.IsValid() {
+		data.BoundSourceIP = types.String{Value: result.localIP.String()}
+		data.UsedStack = types.String{Value: ipVersion(result.localIP.Unmap())}
+	}
+	data.IsNAT = isBehindNAT(ip.Unmap(), result.localIP.Unmap())
+	data.ResponseTimeMS = types.Int64{Value: result.responseTime.Milliseconds()}
+	data.LatencyMS = types.Int64{Value: result.latency.Milliseconds()}
+	data.CheckedAt = types.String{Null: true}
+	if !d.provider.suppressCheckedAt {
+		data.CheckedAt = types.String{Value: result.checkedAt.UTC().Format(time.RFC3339)}
+	}
+	data.RequestID = types.String{Null: true}
+	if result.requestID != "" {
+		data.RequestID = types.String{Value: result.requestID}
+	}
+
+	data.RawJSON = types.String{Value: string(result.body)}
+
+	data.All = scalarMap(data, "id", "all", "raw_json")
 
 	log.Printf("got to state update ✅: %+v", data)
 
@@ -274,3 +866,59 @@ func ipVersion(netIP netaddr.IP) string {
 
 	return IPUnknown
 }
+
+// coordinateValues converts a coordinate into a (rounded) float and its exact textual representation.
+func coordinateValues(coordinate json.Number) (types.Float64, types.String) {
+	if coordinate == "" {
+		return types.Float64{Null: true}, types.String{Null: true}
+	}
+
+	value, err := coordinate.Float64()
+	if err != nil {
+		log.Printf("Coordinate '%s' is not a float 🚨: %s", coordinate, err)
+		return types.Float64{Null: true}, types.String{Value: coordinate.String()}
+	}
+
+	return types.Float64{Value: value}, types.String{Value: coordinate.String()}
+}
+
+// decimalValue converts a (possibly 128 bit) integer into a number without losing precision.
+func decimalValue(decimal json.Number) types.Number {
+	if decimal == "" {
+		return types.Number{Null: true}
+	}
+
+	value, _, err := big.ParseFloat(decimal.String(), 10, 128, big.ToNearestEven)
+	if err != nil {
+		log.Printf("Decimal '%s' is not a number 🚨: %s", decimal, err)
+		return types.Number{Null: true}
+	}
+
+	return types.Number{Value: value}
+}
+
+// ipCIDR returns the network of the IP with the given prefix length or the default prefix length of the IP version.
+func ipCIDR(netIP netaddr.IP, prefixLength types.Int64) (netaddr.IPPrefix, error) {
+	bits := int64(DefaultPrefixLengthIPv4)
+	if netIP.Is6() {
+		bits = DefaultPrefixLengthIPv6
+	}
+	if !prefixLength.Null {
+		bits = prefixLength.Value
+	}
+
+	if bits < 0 || bits > int64(netIP.BitLen()) {
+		return netaddr.IPPrefix{}, fmt.Errorf("the prefix_length value '%d' must be between 0 and %d for the IP '%s'", bits, netIP.BitLen(), netIP)
+	}
+
+	return netIP.Prefix(uint8(bits))
+}
+
+// ipURLHost formats the IP for the use as host in a URL, see RFC 3986 and RFC 6874.
+func ipURLHost(netIP netaddr.IP) string {
+	if netIP.Is6() {
+		return "[" + strings.Replace(netIP.String(), "%", "%25", 1) + "]"
+	}
+
+	return netIP.String()
+}
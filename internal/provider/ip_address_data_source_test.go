@@ -1,9 +1,35 @@
 package provider
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"inet.af/netaddr"
 )
 
 func TestIpAddressDataSource(t *testing.T) {
@@ -44,10 +70,1609 @@ func TestIpAddressDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.publicip_address.v4", "source_ip", "0.0.0.0"),
 				),
 			},
+			{
+				Config: v4VersionConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.publicip_address.v4_version", "ip"),
+					resource.TestCheckResourceAttr("data.publicip_address.v4_version", "ip_version", "v4"),
+					resource.TestCheckResourceAttr("data.publicip_address.v4_version", "is_ipv4", "true"),
+				),
+			},
+			{
+				Config: v4PreferConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.publicip_address.v4_prefer", "ip"),
+					resource.TestCheckResourceAttrSet("data.publicip_address.v4_prefer", "used_stack"),
+				),
+			},
+			{
+				Config: geoConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.publicip_address.geo", "ip"),
+					resource.TestCheckResourceAttrSet("data.publicip_address.geo", "country"),
+				),
+			},
 		},
 	})
 }
 
+// testReadIPAddress reads the publicip_address data source from the mock IP information provider at serverURL.
+// The provider is configured with the providerAttributes and the data source with the attributes.
+func testReadIPAddress(t *testing.T, serverURL string, providerAttributes, attributes map[string]tftypes.Value) (IpDataSourceModel, diag.Diagnostics) {
+	t.Helper()
+
+	configured := map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, serverURL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	}
+	for name, value := range providerAttributes {
+		configured[name] = value
+	}
+
+	var data IpDataSourceModel
+	resp := testReadDataSource(t, NewIpDataSource, testProviderData(t, configured), attributes)
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(context.Background(), &data)
+	}
+	return data, resp.Diagnostics
+}
+
+// testLoopbackInterface returns the name of the loopback interface, or skips the test if there is none.
+func testLoopbackInterface(t *testing.T) string {
+	t.Helper()
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			return iface.Name
+		}
+	}
+	t.Skip("no loopback interface")
+	return ""
+}
+
+func TestIpAddressDataSourceResponse(t *testing.T) {
+	before := time.Now().Truncate(time.Second)
+
+	tests := map[string]struct {
+		body               string
+		status             int
+		providerAttributes map[string]tftypes.Value
+		attributes         map[string]tftypes.Value
+		failed             bool
+		check              func(t *testing.T, data IpDataSourceModel, diags diag.Diagnostics)
+	}{
+		"geolocation": {
+			body: `{"ip":"203.0.113.4","country":"Switzerland","country_iso":"CH","region_name":"Zurich","region_code":"ZH","city":"Zurich","zip_code":"8005","latitude":47.3682,"longitude":8.5671,"time_zone":"Europe/Zurich"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				for value, actual := range map[string]types.String{
+					"Switzerland":   data.Country,
+					"CH":            data.CountryISO,
+					"Zurich":        data.RegionName,
+					"ZH":            data.RegionCode,
+					"8005":          data.ZIPCode,
+					"Europe/Zurich": data.TimeZone,
+				} {
+					if actual.Value != value {
+						t.Errorf("expected '%s', got '%s'", value, actual.Value)
+					}
+				}
+				if data.City.Value != "Zurich" || data.Latitude.Value != 47.3682 || data.Longitude.Value != 8.5671 {
+					t.Errorf("unexpected location: %+v", data)
+				}
+			},
+		},
+		"hostname": {
+			body: `{"ip":"203.0.113.4","hostname":"host.example.net"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if data.Hostname.Value != "host.example.net" {
+					t.Errorf("expected hostname 'host.example.net', got %v", data.Hostname)
+				}
+			},
+		},
+		"absent fields": {
+			body: `{"ip":"203.0.113.4","asn":"AS3320"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.Hostname.Null || !data.CountryEU.Null || !data.ASNEU.Null {
+					t.Errorf("expected hostname, country_eu and asn_eu to be null, got %v, %v and %v", data.Hostname, data.CountryEU, data.ASNEU)
+				}
+			},
+		},
+		"country_eu": {
+			body: `{"ip":"203.0.113.4","country_iso":"DE","country_eu":true}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.CountryEU.Equal(types.Bool{Value: true}) {
+					t.Errorf("expected country_eu true, got %v", data.CountryEU)
+				}
+			},
+		},
+		"not country_eu": {
+			body: `{"ip":"203.0.113.4","country_iso":"CH","country_eu":false}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.CountryEU.Equal(types.Bool{Value: false}) {
+					t.Errorf("expected country_eu false, got %v", data.CountryEU)
+				}
+			},
+		},
+		"raw_json": {
+			body: `{"ip":"203.0.113.4","unmodeled":{"nested":[1,2,3]}}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if data.RawJSON.Value != `{"ip":"203.0.113.4","unmodeled":{"nested":[1,2,3]}}` {
+					t.Errorf("expected the unaltered response as raw_json, got '%s'", data.RawJSON.Value)
+				}
+				if _, ok := data.All.Elems["raw_json"]; ok {
+					t.Errorf("expected raw_json to be omitted from all")
+				}
+			},
+		},
+		"nat": {
+			body: `{"ip":"203.0.113.4"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.IsNAT.Equal(types.Bool{Value: true}) {
+					t.Errorf("expected is_nat true, got %v", data.IsNAT)
+				}
+			},
+		},
+		"no nat": {
+			body: `{"ip":"127.0.0.1"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.IsNAT.Equal(types.Bool{Value: false}) {
+					t.Errorf("expected is_nat false, got %v", data.IsNAT)
+				}
+			},
+		},
+		"checked_at": {
+			body: `{"ip":"203.0.113.4"}`,
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				checkedAt, err := time.Parse(time.RFC3339, data.CheckedAt.Value)
+				if err != nil || checkedAt.Before(before) || checkedAt.After(time.Now()) {
+					t.Errorf("expected checked_at to be the time of the lookup, got '%s': %v", data.CheckedAt.Value, err)
+				}
+				if strings.Contains(data.ID.Value, data.CheckedAt.Value) {
+					t.Errorf("expected the id '%s' not to contain checked_at", data.ID.Value)
+				}
+			},
+		},
+		"suppress_checked_at": {
+			body:               `{"ip":"203.0.113.4"}`,
+			providerAttributes: map[string]tftypes.Value{"suppress_checked_at": tftypes.NewValue(tftypes.Bool, true)},
+			check: func(t *testing.T, data IpDataSourceModel, _ diag.Diagnostics) {
+				if !data.CheckedAt.Null {
+					t.Errorf("expected checked_at to be null, got '%s'", data.CheckedAt.Value)
+				}
+			},
+		},
+		"require_geo without geolocation": {
+			body:       `{"ip":"203.0.113.4"}`,
+			attributes: map[string]tftypes.Value{"require_geo": tftypes.NewValue(tftypes.Bool, true)},
+			failed:     true,
+		},
+		"require_geo with empty geolocation": {
+			body:       `{"ip":"203.0.113.4","country":"","city":""}`,
+			attributes: map[string]tftypes.Value{"require_geo": tftypes.NewValue(tftypes.Bool, true)},
+		},
+		"require_geo with geolocation": {
+			body:       `{"ip":"203.0.113.4","country":"Switzerland"}`,
+			attributes: map[string]tftypes.Value{"require_geo": tftypes.NewValue(tftypes.Bool, true)},
+		},
+		"rate limited": {
+			status: http.StatusTooManyRequests,
+			failed: true,
+			check: func(t *testing.T, _ IpDataSourceModel, diags diag.Diagnostics) {
+				if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, remediations[errorClassRateLimited]) {
+					t.Errorf("expected the rate limit remediation, got '%s'", detail)
+				}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/json" {
+					t.Errorf("unexpected request: %s", r.URL)
+				}
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+					return
+				}
+				_, _ = w.Write([]byte(test.body))
+			})
+
+			// The RDAP service must only be asked, if lookup_asn_eu is set.
+			providerAttributes := map[string]tftypes.Value{"rdap_url": tftypes.NewValue(tftypes.String, server.URL+"/rdap/")}
+			for name, value := range test.providerAttributes {
+				providerAttributes[name] = value
+			}
+
+			data, diags := testReadIPAddress(t, server.URL, providerAttributes, test.attributes)
+			if diags.HasError() != test.failed {
+				t.Fatalf("expected failure %t, got %v", test.failed, diags)
+			}
+			if test.check != nil {
+				test.check(t, data, diags)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceRequestHeaders(t *testing.T) {
+	var received http.Header
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	headers := func(values map[string]string) tftypes.Value {
+		elems := map[string]tftypes.Value{}
+		for name, value := range values {
+			elems[name] = tftypes.NewValue(tftypes.String, value)
+		}
+		return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elems)
+	}
+
+	tests := map[string]struct {
+		providerAttributes map[string]tftypes.Value
+		attributes         map[string]tftypes.Value
+		expected           map[string]string
+	}{
+		"default": {
+			expected: map[string]string{"User-Agent": UserAgent + " (test)", "Authorization": ""},
+		},
+		"user_agent_comment": {
+			providerAttributes: map[string]tftypes.Value{"user_agent_comment": tftypes.NewValue(tftypes.String, "acme")},
+			expected:           map[string]string{"User-Agent": UserAgent + " (test) (acme)"},
+		},
+		"user_agent": {
+			providerAttributes: map[string]tftypes.Value{"user_agent": tftypes.NewValue(tftypes.String, "acme-bot/1.0")},
+			expected:           map[string]string{"User-Agent": "acme-bot/1.0"},
+		},
+		"user_agent and user_agent_comment": {
+			providerAttributes: map[string]tftypes.Value{
+				"user_agent":         tftypes.NewValue(tftypes.String, "acme-bot/1.0"),
+				"user_agent_comment": tftypes.NewValue(tftypes.String, "+https://acme.example"),
+			},
+			expected: map[string]string{"User-Agent": "acme-bot/1.0 (+https://acme.example)"},
+		},
+		"api_token": {
+			providerAttributes: map[string]tftypes.Value{"api_token": tftypes.NewValue(tftypes.String, "secret")},
+			expected:           map[string]string{"Authorization": "Bearer secret"},
+		},
+		"basic auth": {
+			providerAttributes: map[string]tftypes.Value{
+				"username": tftypes.NewValue(tftypes.String, "terraform"),
+				"password": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			expected: map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("terraform:secret"))},
+		},
+		"headers": {
+			attributes: map[string]tftypes.Value{"headers": headers(map[string]string{"Authorization": "Bearer secret", "User-Agent": "custom"})},
+			expected:   map[string]string{"Authorization": "Bearer secret", "User-Agent": "custom"},
+		},
+		"provider headers": {
+			providerAttributes: map[string]tftypes.Value{"headers": headers(map[string]string{"X-Api-Key": "provider"})},
+			expected:           map[string]string{"X-Api-Key": "provider"},
+		},
+		"overridden provider headers": {
+			providerAttributes: map[string]tftypes.Value{"headers": headers(map[string]string{"X-Api-Key": "provider"})},
+			attributes:         map[string]tftypes.Value{"headers": headers(map[string]string{"x-api-key": "data-source"})},
+			expected:           map[string]string{"X-Api-Key": "data-source"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := testReadIPAddress(t, server.URL, test.providerAttributes, test.attributes)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			for header, expected := range test.expected {
+				if actual := received.Get(header); actual != expected {
+					t.Errorf("expected the %s header '%s', got '%s'", header, expected, actual)
+				}
+			}
+		})
+	}
+
+	data, diags := testReadIPAddress(t, server.URL, map[string]tftypes.Value{
+		"request_id_header": tftypes.NewValue(tftypes.String, "X-Correlation-ID"),
+	}, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if requestID := received.Get("X-Correlation-ID"); requestID == "" || data.RequestID.Value != requestID {
+		t.Errorf("expected request_id to be the sent request id '%s', got '%s'", requestID, data.RequestID.Value)
+	}
+}
+
+func TestIpAddressDataSourceTLS(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	clientCertServer := httptest.NewUnstartedServer(handler)
+	clientCertServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	clientCertServer.StartTLS()
+	t.Cleanup(clientCertServer.Close)
+	tls12Server := httptest.NewUnstartedServer(handler)
+	tls12Server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	tls12Server.StartTLS()
+	t.Cleanup(tls12Server.Close)
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, caCert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	certPEM, keyPEM := testKeyPair(t, x509.ExtKeyUsageClientAuth)
+	hash := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pins := func(pin string) tftypes.Value {
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, pin)})
+	}
+	insecure := tftypes.NewValue(tftypes.Bool, true)
+
+	tests := map[string]struct {
+		server             *httptest.Server
+		providerAttributes map[string]tftypes.Value
+		failed             bool
+	}{
+		"unknown CA": {server, nil, true},
+		"insecure_skip_verify": {server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+		}, false},
+		"ca_cert_pem": {server, map[string]tftypes.Value{
+			"ca_cert_pem": tftypes.NewValue(tftypes.String, string(caCert)),
+		}, false},
+		"ca_cert_file": {server, map[string]tftypes.Value{
+			"ca_cert_file": tftypes.NewValue(tftypes.String, caCertFile),
+		}, false},
+		"without client certificate": {clientCertServer, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+		}, true},
+		"client certificate": {clientCertServer, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"client_cert_pem":      tftypes.NewValue(tftypes.String, string(certPEM)),
+			"client_key_pem":       tftypes.NewValue(tftypes.String, string(keyPEM)),
+		}, false},
+		"tls_min_version 1.2": {tls12Server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"tls_min_version":      tftypes.NewValue(tftypes.String, "1.2"),
+		}, false},
+		"tls_min_version 1.3": {tls12Server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"tls_min_version":      tftypes.NewValue(tftypes.String, "1.3"),
+		}, true},
+		"pin with sha256 prefix": {server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"pinned_spki_hashes":   pins("sha256/" + base64.StdEncoding.EncodeToString(hash[:])),
+		}, false},
+		"pin": {server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"pinned_spki_hashes":   pins(base64.StdEncoding.EncodeToString(hash[:])),
+		}, false},
+		"other pin": {server, map[string]tftypes.Value{
+			"insecure_skip_verify": insecure,
+			"pinned_spki_hashes":   pins("sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
+		}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := testReadIPAddress(t, test.server.URL, test.providerAttributes, nil)
+			if diags.HasError() != test.failed {
+				t.Errorf("expected failure %t, got %v", test.failed, diags)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceResponseChecks(t *testing.T) {
+	respond := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	}
+	redirect := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json" {
+			http.Redirect(w, r, "/redirected/json", http.StatusFound)
+			return
+		}
+		respond(w, r)
+	}
+	captivePortal := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><body>Please log in to use the Wi-Fi</body></html>`))
+	}
+
+	tests := map[string]struct {
+		handler            http.HandlerFunc
+		providerAttributes map[string]tftypes.Value
+		failed             bool
+		details            []string
+	}{
+		"redirect": {handler: redirect},
+		"follow_redirects": {
+			handler:            redirect,
+			providerAttributes: map[string]tftypes.Value{"follow_redirects": tftypes.NewValue(tftypes.Bool, false)},
+			failed:             true,
+		},
+		"max_redirects": {
+			handler:            redirect,
+			providerAttributes: map[string]tftypes.Value{"max_redirects": tftypes.NewValue(tftypes.Number, 0)},
+			failed:             true,
+		},
+		"max_response_bytes": {
+			handler:            respond,
+			providerAttributes: map[string]tftypes.Value{"max_response_bytes": tftypes.NewValue(tftypes.Number, 20)},
+		},
+		"max_response_bytes exceeded": {
+			handler:            respond,
+			providerAttributes: map[string]tftypes.Value{"max_response_bytes": tftypes.NewValue(tftypes.Number, 19)},
+			failed:             true,
+			details:            []string{"max_response_bytes of 19 bytes"},
+		},
+		"unexpected Content-Type": {
+			handler: captivePortal,
+			failed:  true,
+			details: []string{"text/html", "Please log in"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := testIPServer(t, test.handler)
+			_, diags := testReadIPAddress(t, server.URL, test.providerAttributes, nil)
+			if diags.HasError() != test.failed {
+				t.Fatalf("expected failure %t, got %v", test.failed, diags)
+			}
+			for _, detail := range test.details {
+				if !strings.Contains(diags.Errors()[0].Detail(), detail) {
+					t.Errorf("expected '%s' in the diagnostic, got: %s", detail, diags.Errors()[0].Detail())
+				}
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceBoundSource(t *testing.T) {
+	loopback := testLoopbackInterface(t)
+	localID := formatID(DefaultIDFormat, "127.0.0.1", "203.0.113.4")
+	localV4ID := formatID(DefaultIDFormat, idSource("127.0.0.1", map[string]string{"ip_version": IPVersion4}), "203.0.113.4")
+
+	tests := map[string]struct {
+		newServer  func(*testing.T, http.HandlerFunc) *httptest.Server
+		ip         string
+		attributes map[string]tftypes.Value
+		skip       bool
+		expected   string
+		expectedID string
+	}{
+		"source_ip": {
+			newServer:  testIPServer,
+			ip:         "203.0.113.4",
+			attributes: map[string]tftypes.Value{"source_ip": tftypes.NewValue(tftypes.String, "0.0.0.0")},
+			expected:   "127.0.0.1",
+		},
+		"ipv6 source_ip": {
+			newServer:  testIPv6Server,
+			ip:         "2001:db8::1",
+			attributes: map[string]tftypes.Value{"source_ip": tftypes.NewValue(tftypes.String, "::")},
+			expected:   "::1",
+		},
+		"strict_source": {
+			newServer: testIPServer,
+			ip:        "203.0.113.4",
+			attributes: map[string]tftypes.Value{
+				"source_ip":     tftypes.NewValue(tftypes.String, "127.0.0.1"),
+				"strict_source": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expected: "127.0.0.1",
+		},
+		"unspecified strict_source": {
+			newServer: testIPServer,
+			ip:        "203.0.113.4",
+			attributes: map[string]tftypes.Value{
+				"source_ip":     tftypes.NewValue(tftypes.String, "0.0.0.0"),
+				"strict_source": tftypes.NewValue(tftypes.Bool, true),
+			},
+			expected: "127.0.0.1",
+		},
+		"source_interface": {
+			newServer: testIPServer,
+			ip:        "203.0.113.4",
+			attributes: map[string]tftypes.Value{
+				"source_interface": tftypes.NewValue(tftypes.String, loopback),
+				"ip_version":       tftypes.NewValue(tftypes.String, IPVersion4),
+			},
+			expected:   "127.0.0.1",
+			expectedID: localV4ID,
+		},
+		"source_cidr": {
+			newServer:  testIPServer,
+			ip:         "203.0.113.4",
+			attributes: map[string]tftypes.Value{"source_cidr": tftypes.NewValue(tftypes.String, "127.0.0.1/8")},
+			expected:   "127.0.0.1",
+			expectedID: localID,
+		},
+		"bind_device": {
+			newServer:  testIPServer,
+			ip:         "203.0.113.4",
+			attributes: map[string]tftypes.Value{"bind_device": tftypes.NewValue(tftypes.String, loopback)},
+			skip:       !bindDeviceSupported,
+			expected:   "127.0.0.1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.skip {
+				t.Skip("not supported on this platform")
+			}
+			server := test.newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprintf(w, `{"ip":"%s"}`, test.ip)
+			})
+
+			data, diags := testReadIPAddress(t, server.URL, nil, test.attributes)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if data.IP.Value != test.ip || data.BoundSourceIP.Value != test.expected {
+				t.Errorf("expected the IP '%s' over bound_source_ip '%s', got '%s' over '%s'", test.ip, test.expected, data.IP.Value, data.BoundSourceIP.Value)
+			}
+			if test.expectedID != "" && data.ID.Value != test.expectedID {
+				t.Errorf("expected the id '%s', got '%s'", test.expectedID, data.ID.Value)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceInvalidSource(t *testing.T) {
+	loopback := testLoopbackInterface(t)
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	tests := map[string]struct {
+		attributes map[string]tftypes.Value
+		detail     string
+	}{
+		"source_ip and source_interface": {attributes: map[string]tftypes.Value{
+			"source_interface": tftypes.NewValue(tftypes.String, loopback),
+			"source_ip":        tftypes.NewValue(tftypes.String, "0.0.0.0"),
+		}},
+		"unknown source_interface": {attributes: map[string]tftypes.Value{
+			"source_interface": tftypes.NewValue(tftypes.String, "does-not-exist0"),
+		}},
+		"source_cidr and ip_version": {attributes: map[string]tftypes.Value{
+			"source_cidr": tftypes.NewValue(tftypes.String, "127.0.0.1/8"),
+			"ip_version":  tftypes.NewValue(tftypes.String, IPVersion6),
+		}, detail: "the source_cidr '127.0.0.1/8'"},
+		"foreign source_cidr": {attributes: map[string]tftypes.Value{
+			"source_cidr": tftypes.NewValue(tftypes.String, "198.51.100.0/24"),
+		}},
+		"invalid source_cidr": {attributes: map[string]tftypes.Value{
+			"source_cidr": tftypes.NewValue(tftypes.String, "not-a-cidr"),
+		}},
+		"foreign strict_source": {attributes: map[string]tftypes.Value{
+			"source_ip":     tftypes.NewValue(tftypes.String, "198.51.100.1"),
+			"strict_source": tftypes.NewValue(tftypes.Bool, true),
+		}, detail: "not present on any local network interface"},
+		"unknown bind_device": {attributes: map[string]tftypes.Value{
+			"bind_device": tftypes.NewValue(tftypes.String, "does-not-exist0"),
+		}},
+		"unknown vrf": {attributes: map[string]tftypes.Value{
+			"vrf": tftypes.NewValue(tftypes.String, "does-not-exist0"),
+		}},
+		"vrf and bind_device": {attributes: map[string]tftypes.Value{
+			"vrf":         tftypes.NewValue(tftypes.String, "lo"),
+			"bind_device": tftypes.NewValue(tftypes.String, "lo"),
+		}},
+		"unknown netns": {attributes: map[string]tftypes.Value{
+			"netns": tftypes.NewValue(tftypes.String, "does-not-exist"),
+		}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := testReadIPAddress(t, server.URL, nil, test.attributes)
+			if !diags.HasError() {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(diags.Errors()[0].Detail(), test.detail) {
+				t.Errorf("expected '%s' in the diagnostic, got: %s", test.detail, diags.Errors()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceIPStack(t *testing.T) {
+	// The mock provider is only reachable over IPv4.
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	tests := map[string]struct {
+		attributes map[string]string
+		failed     bool
+	}{
+		"prefer v6":                 {attributes: map[string]string{"prefer": "v6"}},
+		"prefer v4":                 {attributes: map[string]string{"prefer": "v4"}},
+		"prefer any":                {attributes: map[string]string{"prefer": "any"}},
+		"invalid prefer":            {attributes: map[string]string{"prefer": "v5"}, failed: true},
+		"ip_version v4":             {attributes: map[string]string{"ip_version": "v4"}},
+		"ip_version v4 over v4":     {attributes: map[string]string{"ip_version": "v4", "source_ip": "127.0.0.1"}},
+		"ip_version v6":             {attributes: map[string]string{"ip_version": "v6"}, failed: true},
+		"invalid ip_version":        {attributes: map[string]string{"ip_version": "v5"}, failed: true},
+		"ip_version v6 over source": {attributes: map[string]string{"ip_version": "v6", "source_ip": "127.0.0.1"}, failed: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attributes := map[string]tftypes.Value{}
+			for attribute, value := range test.attributes {
+				attributes[attribute] = tftypes.NewValue(tftypes.String, value)
+			}
+
+			data, diags := testReadIPAddress(t, server.URL, map[string]tftypes.Value{
+				"timeout": tftypes.NewValue(tftypes.String, "1s"),
+			}, attributes)
+			if diags.HasError() != test.failed {
+				t.Fatalf("expected failure %t, got %v", test.failed, diags)
+			}
+			if !test.failed && data.UsedStack.Value != IPVersion4 {
+				t.Errorf("expected used_stack '%s', got '%s'", IPVersion4, data.UsedStack.Value)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceLatencyWarning(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	tests := map[string]struct {
+		threshold string
+		warned    bool
+	}{
+		"slow": {"10ms", true},
+		"fast": {"10s", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			providerData := testProviderData(t, map[string]tftypes.Value{
+				"provider_url":           tftypes.NewValue(tftypes.String, server.URL),
+				"latency_warn_threshold": tftypes.NewValue(tftypes.String, test.threshold),
+			})
+			resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			warned := resp.Diagnostics.WarningsCount() > 0
+			if warned != test.warned {
+				t.Errorf("expected warning %t, got %v", test.warned, resp.Diagnostics)
+			}
+			if warned && !strings.Contains(resp.Diagnostics.Warnings()[0].Detail(), server.URL) {
+				t.Errorf("expected the warning to name '%s', got '%s'", server.URL, resp.Diagnostics.Warnings()[0].Detail())
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceLatency(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	// The handshake with the local server is immediate, while the response is delayed.
+	if data.ResponseTimeMS.Value < 50 || data.LatencyMS.Null || data.LatencyMS.Value >= 50 {
+		t.Errorf("expected a latency below and a response time above 50ms, got %v and %v", data.LatencyMS, data.ResponseTimeMS)
+	}
+}
+
+func TestIpAddressDataSourceUserAgents(t *testing.T) {
+	var receivedUserAgents []string
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedUserAgents = append(receivedUserAgents, r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"user_agents": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "agent-a"),
+			tftypes.NewValue(tftypes.String, "agent-b"),
+		}),
+	})
+	for i := 0; i < 3; i++ {
+		resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+	}
+
+	expected := []string{"agent-a", "agent-b", "agent-a"}
+	if strings.Join(receivedUserAgents, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected user agents %v, got %v", expected, receivedUserAgents)
+	}
+}
+
+func TestIpAddressDataSourceRequestTimeout(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Connects and responds fast, but sends the body slowly.
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	tests := map[string]struct {
+		requestTimeout string
+		failed         bool
+	}{
+		"enough":      {"5s", false},
+		"not enough":  {"100ms", true},
+		"unspecified": {"", true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attributes := map[string]tftypes.Value{
+				"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+				"timeout":      tftypes.NewValue(tftypes.String, "50ms"),
+			}
+			if test.requestTimeout != "" {
+				attributes["request_timeout"] = tftypes.NewValue(tftypes.String, test.requestTimeout)
+			}
+
+			resp := testReadDataSource(t, NewIpDataSource, testProviderData(t, attributes), nil)
+			if resp.Diagnostics.HasError() != test.failed {
+				t.Errorf("expected failure %t, got %v", test.failed, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestIPURLHost(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.4":  "203.0.113.4",
+		"2001:db8::1":  "[2001:db8::1]",
+		"fe80::1%eth0": "[fe80::1%25eth0]",
+	}
+
+	for address, expected := range tests {
+		t.Run(address, func(t *testing.T) {
+			host := ipURLHost(netaddr.MustParseIP(address))
+			if host != expected {
+				t.Errorf("expected '%s', got '%s'", expected, host)
+			}
+			if _, err := url.Parse("https://" + host + "/"); err != nil {
+				t.Errorf("expected '%s' to be usable in a URL: %s", host, err)
+			}
+		})
+	}
+}
+
+func TestIPCIDR(t *testing.T) {
+	tests := map[string]struct {
+		ip           string
+		prefixLength types.Int64
+		expected     string
+	}{
+		"ipv4 default":  {"203.0.113.4", types.Int64{Null: true}, "203.0.113.4/32"},
+		"ipv4 /24":      {"203.0.113.4", types.Int64{Value: 24}, "203.0.113.0/24"},
+		"ipv6 default":  {"2001:db8:1:2:3:4:5:6", types.Int64{Null: true}, "2001:db8:1:2::/64"},
+		"ipv6 /48":      {"2001:db8:1:2:3:4:5:6", types.Int64{Value: 48}, "2001:db8:1::/48"},
+		"ipv6 /128":     {"2001:db8::1", types.Int64{Value: 128}, "2001:db8::1/128"},
+		"ipv4 too long": {"203.0.113.4", types.Int64{Value: 33}, ""},
+		"negative":      {"203.0.113.4", types.Int64{Value: -1}, ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cidr, err := ipCIDR(netaddr.MustParseIP(test.ip), test.prefixLength)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("expected an error, got '%s'", cidr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if cidr.String() != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, cidr)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourceProviderFallback(t *testing.T) {
+	failingServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	brokenServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html>`))
+	})
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_urls": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, failingServer.URL),
+			tftypes.NewValue(tftypes.String, brokenServer.URL),
+			tftypes.NewValue(tftypes.String, server.URL),
+		}),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning about the fallback, got: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.ProviderURLUsed.Value != server.URL {
+		t.Errorf("expected provider_url_used '%s', got '%s'", server.URL, data.ProviderURLUsed.Value)
+	}
+}
+
+func TestIpAddressDataSourceParallelProviders(t *testing.T) {
+	slowServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`{"ip":"198.51.100.1"}`))
+	})
+	fastServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_urls": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, slowServer.URL),
+			tftypes.NewValue(tftypes.String, fastServer.URL),
+		}),
+		"parallel_providers": tftypes.NewValue(tftypes.Bool, true),
+		"rate_limit_burst":   tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	start := time.Now()
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if time.Since(start) >= 2*time.Second {
+		t.Errorf("expected the slow provider to be cancelled, took %s", time.Since(start))
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" {
+		t.Errorf("expected the IP of the fast provider, got '%s'", data.IP.Value)
+	}
+	if data.ProviderURLUsed.Value != fastServer.URL {
+		t.Errorf("expected provider_url_used '%s', got '%s'", fastServer.URL, data.ProviderURLUsed.Value)
+	}
+}
+
+func TestIpAddressDataSourceProviderURL(t *testing.T) {
+	defaultServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.1"}`))
+	})
+	overrideServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.2"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, defaultServer.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	for providerURL, expectedIP := range map[string]string{
+		"":                 "203.0.113.1",
+		overrideServer.URL: "203.0.113.2",
+	} {
+		attributes := map[string]tftypes.Value{}
+		if providerURL != "" {
+			attributes["provider_url"] = tftypes.NewValue(tftypes.String, providerURL)
+		}
+		resp := testReadDataSource(t, NewIpDataSource, providerData, attributes)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data IpDataSourceModel
+		resp.State.Get(context.Background(), &data)
+		if data.IP.Value != expectedIP {
+			t.Errorf("expected IP '%s' for provider_url '%s', got '%s'", expectedIP, providerURL, data.IP.Value)
+		}
+	}
+}
+
+func TestIpAddressDataSourceTimeouts(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		"timeout":          tftypes.NewValue(tftypes.String, "100ms"),
+		"total_timeout":    tftypes.NewValue(tftypes.String, "200ms"),
+	})
+
+	// The read timeout replaces both the timeout and the total_timeout of the provider.
+	timeoutsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"read": tftypes.String}}
+	for read, expectError := range map[string]bool{
+		"":      true,
+		"2s":    false,
+		"150ms": true,
+	} {
+		attributes := map[string]tftypes.Value{}
+		if read != "" {
+			attributes["timeouts"] = tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+				"read": tftypes.NewValue(tftypes.String, read),
+			})
+		}
+		resp := testReadDataSource(t, NewIpDataSource, providerData, attributes)
+		if resp.Diagnostics.HasError() != expectError {
+			t.Errorf("expected error %t for the read timeout '%s', got: %v", expectError, read, resp.Diagnostics)
+		}
+	}
+}
+
+func TestIpAddressDataSourceRateLimit(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	// The provider allows one request per minute, which the first read uses up.
+	// Waiting for the rate limit is bounded by the total_timeout.
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":    tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_rate": tftypes.NewValue(tftypes.String, "1m"),
+		"timeout":         tftypes.NewValue(tftypes.String, "200ms"),
+		"total_timeout":   tftypes.NewValue(tftypes.String, "200ms"),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	resp = testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected the rate limit of the provider to be exhausted")
+	}
+
+	for i := 0; i < 3; i++ {
+		resp = testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+			"rate_limit_rate":  tftypes.NewValue(tftypes.String, "1ms"),
+			"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics with the overridden rate limit: %v", resp.Diagnostics)
+		}
+	}
+
+	resp = testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 0),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for a rate_limit_burst of 0")
+	}
+}
+
+func TestIpAddressDataSourceProxyURL(t *testing.T) {
+	// The proxy answers itself instead of forwarding the request to the IP information provider.
+	var proxiedURL string
+	proxy := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		_, _ = w.Write([]byte(`{"ip":"198.51.100.6"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, "http://ifconfig.test/"),
+		"proxy_url":    tftypes.NewValue(tftypes.String, proxy.URL),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if proxiedURL != "http://ifconfig.test/json" {
+		t.Errorf("expected the request through the proxy, got '%s'", proxiedURL)
+	}
+	if data.IP.Value != "198.51.100.6" {
+		t.Errorf("expected the IP of the proxy, got '%s'", data.IP.Value)
+	}
+	if !data.BoundSourceIP.Null || !data.IsNAT.Null {
+		t.Errorf("expected no bound_source_ip and is_nat through a proxy, got '%s' and %v", data.BoundSourceIP.Value, data.IsNAT)
+	}
+}
+
+func TestIpAddressDataSourceUseProxyEnv(t *testing.T) {
+	proxy := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"198.51.100.6"}`))
+	})
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+	// Requests to the loopback are never proxied, so the server is addressed by another name.
+	serverURL := strings.Replace(server.URL, "127.0.0.1", "ifconfig.test", 1)
+	ifconfigTest := func(_ context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	tests := map[string]struct {
+		useProxyEnv interface{}
+		noProxy     string
+		expectedIP  string
+	}{
+		"default":       {nil, "", "198.51.100.6"},
+		"no_proxy":      {nil, "ifconfig.test", "203.0.113.4"},
+		"use_proxy_env": {false, "", "203.0.113.4"},
+	}
+	for name, test := range tests {
+		t.Setenv("ALL_PROXY", proxy.URL)
+		t.Setenv("HTTP_PROXY", "")
+		t.Setenv("http_proxy", "")
+		t.Setenv("NO_PROXY", test.noProxy)
+		t.Setenv("no_proxy", "")
+
+		providerData := testProviderData(t, map[string]tftypes.Value{
+			"provider_url":  tftypes.NewValue(tftypes.String, serverURL),
+			"use_proxy_env": tftypes.NewValue(tftypes.Bool, test.useProxyEnv),
+			"dns_cache_ttl": tftypes.NewValue(tftypes.String, "1m"),
+		})
+		providerData.dnsCache.lookup = ifconfigTest
+		resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", name, resp.Diagnostics)
+		}
+
+		var data IpDataSourceModel
+		resp.State.Get(context.Background(), &data)
+		if data.IP.Value != test.expectedIP {
+			t.Errorf("%s: expected IP '%s', got '%s'", name, test.expectedIP, data.IP.Value)
+		}
+	}
+}
+
+// testKeyPair creates a self-signed certificate for the usage and returns it and its key PEM encoded.
+func testKeyPair(t *testing.T, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform-provider-publicip"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestIpAddressDataSourceTLSLookups(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4","asn":"AS3320"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// The RDAP service has another key than the IP information provider and must not get the client certificate.
+	rdapCertPEM, rdapKeyPEM := testKeyPair(t, x509.ExtKeyUsageServerAuth)
+	rdapCert, err := tls.X509KeyPair(rdapCertPEM, rdapKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rdapClientCerts int
+	rdapServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rdapClientCerts = len(r.TLS.PeerCertificates)
+		_, _ = w.Write([]byte(`{"objectClassName":"autnum","handle":"AS3320","country":"DE"}`))
+	}))
+	rdapServer.TLS = &tls.Config{Certificates: []tls.Certificate{rdapCert}, ClientAuth: tls.RequestClientCert}
+	rdapServer.StartTLS()
+	t.Cleanup(rdapServer.Close)
+
+	certPEM, keyPEM := testKeyPair(t, x509.ExtKeyUsageClientAuth)
+	hash := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":         tftypes.NewValue(tftypes.String, server.URL),
+		"rdap_url":             tftypes.NewValue(tftypes.String, rdapServer.URL+"/"),
+		"insecure_skip_verify": tftypes.NewValue(tftypes.Bool, true),
+		"client_cert_pem":      tftypes.NewValue(tftypes.String, string(certPEM)),
+		"client_key_pem":       tftypes.NewValue(tftypes.String, string(keyPEM)),
+		"pinned_spki_hashes":   tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, base64.StdEncoding.EncodeToString(hash[:]))}),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"lookup_asn_eu": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if !data.ASNEU.Value {
+		t.Errorf("expected the RDAP lookup to succeed, got %+v", data.ASNEU)
+	}
+	if rdapClientCerts != 0 {
+		t.Errorf("expected no client certificate to be presented to the RDAP service, got %d", rdapClientCerts)
+	}
+}
+
+func TestIpAddressDataSourceHTTPVersion(t *testing.T) {
+	var protoMajor int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protoMajor = r.ProtoMajor
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	for version, expected := range map[string]int{"1.1": 1, "2": 2} {
+		providerData := testProviderData(t, map[string]tftypes.Value{
+			"provider_url":         tftypes.NewValue(tftypes.String, server.URL),
+			"insecure_skip_verify": tftypes.NewValue(tftypes.Bool, true),
+			"http_version":         tftypes.NewValue(tftypes.String, version),
+		})
+		resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected error: %v", resp.Diagnostics)
+		}
+		if protoMajor != expected {
+			t.Errorf("expected HTTP/%d with http_version %s, got HTTP/%d", expected, version, protoMajor)
+		}
+	}
+
+	// HTTP/3 is rejected explicitly instead of silently falling back to TCP.
+	resp := provider.ConfigureResponse{}
+	if (&IpProvider{}).configureHTTPVersion(&ProviderModel{HTTPVersion: types.String{Value: "3"}}, &resp) || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "HTTP/3 is not supported") {
+		t.Errorf("expected HTTP/3 to be rejected, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIpAddressDataSourceUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "echoip.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix domain sockets are not supported: %s", err)
+	}
+
+	var host string
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, "unix://"+socketPath),
+		"unix_socket_host": tftypes.NewValue(tftypes.String, "echoip.internal"),
+	})
+	resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if host != "echoip.internal" {
+		t.Errorf("expected the Host header 'echoip.internal', got '%s'", host)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" {
+		t.Errorf("expected the IP '203.0.113.4', got '%s'", data.IP.Value)
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	for contentType, expected := range map[string]bool{
+		"":                                true,
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"application/problem+json":        true,
+		"text/plain; charset=utf-8":       true,
+		"text/html; charset=utf-8":        false,
+		"invalid;;":                       false,
+	} {
+		if _, ok := checkContentType(contentType, DefaultExpectedContentTypes); ok != expected {
+			t.Errorf("expected %t for the Content-Type '%s', got %t", expected, contentType, ok)
+		}
+	}
+
+	if _, ok := checkContentType("text/html", nil); !ok {
+		t.Error("expected any Content-Type to be accepted without expected media types")
+	}
+}
+
+// testIPv6Server starts a mock IP information provider on the IPv6 loopback, or skips the test if it's unavailable.
+func testIPv6Server(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %s", err)
+	}
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIpAddressDataSourceSourceIPHostname(t *testing.T) {
+	servers := map[string]*httptest.Server{
+		IPVersion4: testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+		}),
+		IPVersion6: testIPv6Server(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ip":"2001:db8::1"}`))
+		}),
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(context.Background(), "localhost")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	hasIPv6 := false
+	for _, addr := range addrs {
+		hasIPv6 = hasIPv6 || addr.IP.Equal(net.IPv6loopback)
+	}
+	if !hasIPv6 {
+		t.Skip("localhost does not resolve to the IPv6 loopback")
+	}
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	// localhost resolves to both loopbacks, so the requested or preferred IP stack decides which one is bound.
+	for _, attribute := range []string{"ip_version", "prefer"} {
+		for version, expected := range map[string]string{IPVersion4: "127.0.0.1", IPVersion6: "::1"} {
+			resp := testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+				"provider_url": tftypes.NewValue(tftypes.String, servers[version].URL),
+				"source_ip":    tftypes.NewValue(tftypes.String, "localhost"),
+				attribute:      tftypes.NewValue(tftypes.String, version),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics for %s '%s': %v", attribute, version, resp.Diagnostics)
+			}
+
+			var data IpDataSourceModel
+			resp.State.Get(context.Background(), &data)
+			if data.BoundSourceIP.Value != expected {
+				t.Errorf("expected bound_source_ip '%s' for %s '%s', got '%s'", expected, attribute, version, data.BoundSourceIP.Value)
+			}
+		}
+	}
+}
+
+func TestIpAddressDataSourceNetns(t *testing.T) {
+	if !netnsSupported {
+		t.Skip("network namespaces are not supported on this platform")
+	}
+	if os.Geteuid() != 0 {
+		t.Skip("entering a network namespace requires root privileges")
+	}
+
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	// The own namespace can be entered, as the server is reachable from it.
+	data, diags := testReadIPAddress(t, server.URL, nil, map[string]tftypes.Value{
+		"netns": tftypes.NewValue(tftypes.String, "/proc/self/ns/net"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if data.IP.Value != "203.0.113.4" {
+		t.Errorf("expected IP '203.0.113.4', got '%s'", data.IP.Value)
+	}
+}
+
+func TestIpAddressDataSourceZonedSourceIP(t *testing.T) {
+	loopback := testLoopbackInterface(t)
+	providerData := testDualStackProviderData(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"2001:db8::1"}`))
+	}, "::1")
+	resp := testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"source_ip": tftypes.NewValue(tftypes.String, "::1%"+loopback),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data IpDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.BoundSourceIP.Value != "::1" {
+		t.Errorf("expected bound_source_ip '::1', got '%s'", data.BoundSourceIP.Value)
+	}
+	if data.UsedStack.Value != IPVersion6 {
+		t.Errorf("expected used_stack '%s', got '%s'", IPVersion6, data.UsedStack.Value)
+	}
+}
+
+func TestIpAddressDataSourceIPVersionHeader(t *testing.T) {
+	// The mock returns the IP of the requested version, regardless of the connection.
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-IP-Version") {
+		case "v6":
+			_, _ = w.Write([]byte(`{"ip":"2001:db8::1"}`))
+		case "v4":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.1"}`))
+		default:
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.99"}`))
+		}
+	})
+
+	providerAttributes := map[string]tftypes.Value{
+		"ip_version_header": tftypes.NewValue(tftypes.String, "X-IP-Version"),
+	}
+	for sourceIP, expectedIP := range map[string]string{
+		"":          "203.0.113.99",
+		"0.0.0.0":   "203.0.113.1",
+		"127.0.0.1": "203.0.113.1",
+	} {
+		attributes := map[string]tftypes.Value{}
+		if sourceIP != "" {
+			attributes["source_ip"] = tftypes.NewValue(tftypes.String, sourceIP)
+		}
+		data, diags := testReadIPAddress(t, server.URL, providerAttributes, attributes)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics for source_ip '%s': %v", sourceIP, diags)
+		}
+		if data.IP.Value != expectedIP {
+			t.Errorf("expected IP '%s' for source_ip '%s', got '%s'", expectedIP, sourceIP, data.IP.Value)
+		}
+	}
+}
+
+func TestIpAddressDataSourceASNEU(t *testing.T) {
+	for _, tc := range []struct {
+		asn      string
+		expected bool
+	}{
+		{"AS3320", true},  // Deutsche Telekom, DE
+		{"AS3303", false}, // Swisscom, CH
+	} {
+		server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/json":
+				_, _ = fmt.Fprintf(w, `{"ip":"203.0.113.4","asn":"%s"}`, tc.asn)
+			case "/rdap/autnum/3320":
+				_, _ = w.Write([]byte(`{"objectClassName":"autnum","handle":"AS3320","country":"DE"}`))
+			case "/rdap/autnum/3303":
+				_, _ = w.Write([]byte(`{"objectClassName":"autnum","handle":"AS3303","country":"CH"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+
+		data, diags := testReadIPAddress(t, server.URL, map[string]tftypes.Value{
+			"rdap_url": tftypes.NewValue(tftypes.String, server.URL+"/rdap/"),
+		}, map[string]tftypes.Value{
+			"lookup_asn_eu": tftypes.NewValue(tftypes.Bool, true),
+		})
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics for '%s': %v", tc.asn, diags)
+		}
+		if data.ASNEU.Null || data.ASNEU.Value != tc.expected {
+			t.Errorf("expected asn_eu %t for '%s', got %+v", tc.expected, tc.asn, data.ASNEU)
+		}
+	}
+}
+
+func TestIpAddressDataSourceTorExit(t *testing.T) {
+	newDataSource := func() datasource.DataSource {
+		return &IPDataSource{lookupHost: func(_ context.Context, host string) ([]string, error) {
+			if host == "4.113.0.203.dnsel.torproject.org" {
+				return []string{"127.0.0.2"}, nil
+			}
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}}
+	}
+
+	tests := map[string]struct {
+		ip       string
+		lookup   bool
+		expected types.Bool
+	}{
+		"exit":       {"203.0.113.4", true, types.Bool{Value: true}},
+		"no exit":    {"203.0.113.5", true, types.Bool{Value: false}},
+		"not looked": {"203.0.113.4", false, types.Bool{Null: true}},
+		"ipv6":       {"2001:db8::1", true, types.Bool{Null: true}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprintf(w, `{"ip":"%s"}`, test.ip)
+			})
+
+			providerData := testProviderData(t, map[string]tftypes.Value{
+				"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+			})
+			resp := testReadDataSource(t, newDataSource, providerData, map[string]tftypes.Value{
+				"lookup_tor_exit": tftypes.NewValue(tftypes.Bool, test.lookup),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data IpDataSourceModel
+			resp.State.Get(context.Background(), &data)
+			if !data.IsTorExit.Equal(test.expected) {
+				t.Errorf("expected is_tor_exit %v, got %v", test.expected, data.IsTorExit)
+			}
+		})
+	}
+}
+
+func TestIpAddressDataSourcePrivacyService(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/json":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+		case r.URL.Path == "/ipinfo/203.0.113.4/privacy" && r.Header.Get("Authorization") == "Bearer secret":
+			_, _ = w.Write([]byte(`{"vpn":true,"proxy":false,"tor":false,"relay":false,"hosting":true,"service":"Example VPN"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	data, diags := testReadIPAddress(t, server.URL, map[string]tftypes.Value{
+		"privacy_service":  tftypes.NewValue(tftypes.String, ReputationServiceIPInfo),
+		"privacy_endpoint": tftypes.NewValue(tftypes.String, server.URL+"/ipinfo/"),
+		"privacy_api_key":  tftypes.NewValue(tftypes.String, "secret"),
+	}, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !data.IsVPN.Value || data.IsProxy.Null || data.IsProxy.Value || !data.IsHosting.Value {
+		t.Errorf("unexpected privacy detection: vpn %v, proxy %v, hosting %v", data.IsVPN, data.IsProxy, data.IsHosting)
+	}
+
+	data, diags = testReadIPAddress(t, server.URL, nil, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !data.IsVPN.Null || !data.IsProxy.Null || !data.IsHosting.Null {
+		t.Errorf("expected no privacy detection without a privacy_service: vpn %v, proxy %v, hosting %v", data.IsVPN, data.IsProxy, data.IsHosting)
+	}
+}
+
+func TestIpAddressDataSourcePreferFallback(t *testing.T) {
+	// The mock provider is only reachable over IPv4.
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+	brokenServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html>`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	// The connection over IPv6 fails, so IPv4 is used and the failure is kept as warning.
+	resp := testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+		"prefer":       tftypes.NewValue(tftypes.String, "v6"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics[0].Summary() != "Fell back to another IP stack" {
+		t.Errorf("expected a warning about the fallback, got: %v", resp.Diagnostics)
+	}
+
+	// The response can't be parsed, which doesn't depend on the IP stack.
+	resp = testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, brokenServer.URL),
+		"prefer":       tftypes.NewValue(tftypes.String, "v4"),
+	})
+	if resp.Diagnostics.WarningsCount() != 0 || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), remediations[errorClassParse]) {
+		t.Errorf("expected the parse error without a fallback, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIpAddressDataSourcePreferTotalTimeout(t *testing.T) {
+	// The Unix domain socket is reachable over both IP stacks, but always fails transiently.
+	socketPath := filepath.Join(t.TempDir(), "echoip.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix domain sockets are not supported: %s", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, "unix://"+socketPath),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+		"max_retries":      tftypes.NewValue(tftypes.Number, 100),
+		"retry_budget":     tftypes.NewValue(tftypes.Number, 100),
+		"retry_min_delay":  tftypes.NewValue(tftypes.String, "10ms"),
+		"retry_max_delay":  tftypes.NewValue(tftypes.String, "10ms"),
+		"total_timeout":    tftypes.NewValue(tftypes.String, "300ms"),
+	})
+
+	// Both IP stacks share the total_timeout of the read.
+	start := time.Now()
+	resp := testReadDataSource(t, NewIpDataSource, providerData, map[string]tftypes.Value{
+		"prefer": tftypes.NewValue(tftypes.String, "v4"),
+	})
+	if elapsed := time.Since(start); elapsed > 550*time.Millisecond {
+		t.Errorf("expected the read to end after the total_timeout, took %s", elapsed)
+	}
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected the total timeout to be exceeded")
+	}
+}
+
+func TestNetworkIPVersion(t *testing.T) {
+	for network, expected := range map[string]string{
+		"tcp":  IPUnknown,
+		"tcp4": IPVersion4,
+		"tcp6": IPVersion6,
+		"udp4": IPVersion4,
+		"udp6": IPVersion6,
+	} {
+		if actual := networkIPVersion(network); actual != expected {
+			t.Errorf("expected '%s' for '%s', got '%s'", expected, network, actual)
+		}
+	}
+}
+
 const defaultConfig = `
 data "publicip_address" "default" {
 }
@@ -64,3 +1689,21 @@ data "publicip_address" "v4" {
   source_ip = "0.0.0.0"
 }
 `
+
+const v4VersionConfig = `
+data "publicip_address" "v4_version" {
+  ip_version = "v4"
+}
+`
+
+const v4PreferConfig = `
+data "publicip_address" "v4_prefer" {
+  prefer = "v4"
+}
+`
+
+const geoConfig = `
+data "publicip_address" "geo" {
+  require_geo = true
+}
+`
@@ -0,0 +1,579 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+// ipFetchResult is the outcome of a successful request to the IP information provider.
+type ipFetchResult struct {
+	respData     *IPResponse
+	ip           netaddr.IP
+	providerURL  string
+	requestURL   string
+	requestID    string
+	responseTime time.Duration
+	// checkedAt is the time, when the request was sent.
+	checkedAt time.Time
+	// latency is the round-trip time of the connection, i.e. the duration of the TCP handshake.
+	// For a reused connection, it's the time to the first byte of the response, which includes the processing.
+	latency time.Duration
+	// localIP is the local address of the connection to the IP information provider.
+	// It's unknown if the connection is made through a proxy.
+	localIP netaddr.IP
+	// body is the unaltered response of the IP information provider.
+	body []byte
+}
+
+// fetchIPFromProviders requests the information from the configured IP information providers.
+// The query, e.g. `ip=…` to look up a specific IP, is added to the request URL.
+func fetchIPFromProviders(ctx context.Context, p *ProviderModel, client *http.Client, query url.Values) (*ipFetchResult, diag.Diagnostics) {
+	result, _, diags := fetchIPFromURLs(ctx, p, client, nil, query)
+	return result, diags
+}
+
+// fetchIPFromURLs requests the information from the given IP information providers instead of the configured ones,
+// unless providerURLs is nil. The returned retryHint combines the hints of all failed requests.
+func fetchIPFromURLs(ctx context.Context, p *ProviderModel, client *http.Client, providerURLs []*url.URL, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	var result *ipFetchResult
+	var hint retryHint
+	var diags diag.Diagnostics
+	switch {
+	case providerURLs == nil && len(p.consensusURLs) > 0:
+		result, hint, diags = fetchIPByConsensus(ctx, p, client, query)
+	case providerURLs == nil:
+		providerURLs = p.ipProviderURLs
+		fallthrough
+	default:
+		if p.parallelProviders && len(providerURLs) > 1 {
+			result, hint, diags = fetchIPInParallel(ctx, p, client, providerURLs, query)
+		} else {
+			result, hint, diags = fetchIPWithFallback(ctx, p, client, providerURLs, query)
+		}
+	}
+	return result, hint, checkTotalTimeout(ctx, diags)
+}
+
+// fetchIPOverNetwork fetches the public IP from the IP information providers over the given network, e.g. 'tcp6'.
+// It fails, if the IP information provider returns an IP of the other version.
+func fetchIPOverNetwork(ctx context.Context, p *ProviderModel, network string) (*ipFetchResult, diag.Diagnostics) {
+	client := newHTTPClient(p, network, netaddr.IP{})
+	result, diags := fetchIPFromProviders(ctx, p, client, nil)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if networkIPVersion(network) != ipVersion(result.ip) {
+		diags.AddError("Unexpected IP version", fmt.Sprintf("The IP information provider returned '%s' for a request over '%s'.", result.ip, network))
+		return nil, diags
+	}
+
+	return result, diags
+}
+
+// noPublicIP reports that neither the public IPv4 nor the public IPv6 could be determined.
+func noPublicIP(diags *diag.Diagnostics, details string) {
+	diags.AddError("No public IP", "Neither the public IPv4 nor the public IPv6 could be determined. "+details)
+}
+
+// resolveTargetIP returns the IP of the ip attribute of a data source. If it's not set, the public IP is fetched from
+// the IP information providers instead and their response is returned as well.
+func resolveTargetIP(ctx context.Context, p *ProviderModel, ipAttribute types.String) (netaddr.IP, *ipFetchResult, diag.Diagnostics) {
+	if ipAttribute.Null || ipAttribute.Unknown {
+		client := newHTTPClient(p, "tcp", netaddr.IP{})
+		result, diags := fetchIPFromProviders(ctx, p, client, nil)
+		if diags.HasError() {
+			return netaddr.IP{}, nil, diags
+		}
+		return result.ip, result, diags
+	}
+
+	var diags diag.Diagnostics
+	ip, err := netaddr.ParseIP(ipAttribute.Value)
+	if err != nil {
+		log.Printf("Could not parse IP '%s' 🚨: %s", ipAttribute.Value, err)
+		diags.AddError("Invalid IP", fmt.Sprintf("The ip '%s' could not be parsed: %s", ipAttribute.Value, err))
+		return netaddr.IP{}, nil, diags
+	}
+	return ip, nil, diags
+}
+
+// totalTimeoutKey is the context key of the total_timeout applied by withTotalTimeout.
+type totalTimeoutKey struct{}
+
+// totalTimeout is the timeout of a whole read, the attribute, which configures it, and its deadline.
+type totalTimeout struct {
+	attribute string
+	timeout   time.Duration
+	deadline  time.Time
+}
+
+// withTotalTimeout bounds a whole read of a data source, including all lookups, retries and waits, by the total_timeout.
+// It's applied once at the top of Read, so that all lookups of the read share the deadline.
+func withTotalTimeout(ctx context.Context, p *ProviderModel) (context.Context, context.CancelFunc) {
+	// Without a configured provider, no lookup can be made.
+	if p == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return withReadTimeout(ctx, "total_timeout", p.totalTimeout)
+}
+
+// withReadTimeout bounds a whole read of a data source by the timeout of the attribute instead of the total_timeout.
+func withReadTimeout(ctx context.Context, attribute string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	deadline := time.Now().Add(timeout)
+	ctx = context.WithValue(ctx, totalTimeoutKey{}, totalTimeout{attribute, timeout, deadline})
+	return context.WithDeadline(ctx, deadline)
+}
+
+// checkTotalTimeout adds an explanation to the failed diagnostics, if the total_timeout of the read was exceeded.
+func checkTotalTimeout(ctx context.Context, diags diag.Diagnostics) diag.Diagnostics {
+	total, ok := ctx.Value(totalTimeoutKey{}).(totalTimeout)
+	if !ok || !diags.HasError() || !errors.Is(ctx.Err(), context.DeadlineExceeded) || time.Now().Before(total.deadline) {
+		return diags
+	}
+
+	log.Printf("total timeout exceeded 🚨: %s", total.timeout)
+	diags.AddError("Total timeout exceeded", withRemediation(errorClassTimeout, withRetryability(true, fmt.Sprintf("The lookup at the IP information provider took longer than the %s of %s, including all retries and waits for the rate limit.", total.attribute, total.timeout))))
+	return diags
+}
+
+// fetchIPWithFallback queries the IP information providers in order and returns the first successful response.
+// A provider is skipped if it can't be reached, responds with an error or its response can't be parsed.
+func fetchIPWithFallback(ctx context.Context, p *ProviderModel, client *http.Client, providerURLs []*url.URL, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	var failures diag.Diagnostics
+	var failureHint retryHint
+	for i, providerURL := range providerURLs {
+		result, hint, diags := fetchIPWithRetries(ctx, p, client, providerURL, query)
+		if !diags.HasError() {
+			if i > 0 {
+				var reasons []string
+				for _, e := range failures.Errors() {
+					reasons = append(reasons, e.Detail())
+				}
+				diags.AddWarning("Fell back to another IP information provider", fmt.Sprintf("The IP was fetched from '%s', because the IP information providers before it failed:\n%s", providerURL, strings.Join(reasons, "\n")))
+			}
+			return result, retryHint{}, diags
+		}
+		failures.Append(diags...)
+		failureHint = failureHint.join(hint)
+
+		if i == len(providerURLs)-1 || ctx.Err() != nil {
+			break
+		}
+		log.Printf("falling back from '%s' to '%s' 🔀", providerURL, providerURLs[i+1])
+	}
+	return nil, failureHint, failures
+}
+
+// fetchIPInParallel queries the IP information providers concurrently and returns the fastest successful response.
+// The remaining requests are cancelled.
+func fetchIPInParallel(ctx context.Context, p *ProviderModel, client *http.Client, providerURLs []*url.URL, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	if p.parallelProvidersLimit > 0 && p.parallelProvidersLimit < len(providerURLs) {
+		providerURLs = providerURLs[:p.parallelProvidersLimit]
+	}
+
+	parallelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result *ipFetchResult
+		hint   retryHint
+		diags  diag.Diagnostics
+	}
+	outcomes := make(chan outcome, len(providerURLs))
+	for _, providerURL := range providerURLs {
+		go func(providerURL *url.URL) {
+			result, hint, diags := fetchIPWithRetries(parallelCtx, p, client, providerURL, query)
+			outcomes <- outcome{result, hint, diags}
+		}(providerURL)
+	}
+
+	var hint retryHint
+	var diags diag.Diagnostics
+	for range providerURLs {
+		o := <-outcomes
+		if !o.diags.HasError() {
+			log.Printf("fastest IP information provider 🏁: %s", o.result.providerURL)
+			return o.result, retryHint{}, o.diags
+		}
+		hint = hint.join(o.hint)
+		diags.Append(o.diags...)
+	}
+	return nil, hint, diags
+}
+
+// fetchIPWithRetries requests the information from the IP information provider at baseURL
+// and retries failed requests up to max_retries times, as long as the retry budget allows it.
+// The returned retryHint is the one of the last request.
+func fetchIPWithRetries(ctx context.Context, p *ProviderModel, client *http.Client, baseURL *url.URL, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	for attempt := int64(0); ; attempt++ {
+		result, hint, diags := fetchIP(ctx, p, client, baseURL, query)
+		maxRetries := p.maxRetries
+		if hint.after > 0 && maxRetries == 0 {
+			// The IP information provider explicitly asked to come back later.
+			maxRetries = 1
+		}
+		if !diags.HasError() || !hint.transient || attempt >= maxRetries || ctx.Err() != nil {
+			return result, hint, diags
+		}
+
+		if hint.after > 0 {
+			deadline, hasDeadline := ctx.Deadline()
+			if hint.after > p.retryMaxDelay || (hasDeadline && time.Now().Add(hint.after).After(deadline)) {
+				log.Printf("Retry-After too long 🚨: not retrying '%s' in %s", baseURL, hint.after)
+				diags.AddWarning("Retry-After too long", fmt.Sprintf("The request to '%s' was not retried, because the IP information provider asked to wait %s, which is longer than the retry_max_delay or the timeout.", baseURL, hint.after))
+				return result, hint, diags
+			}
+		}
+
+		if !p.retryBudget.take() {
+			log.Printf("retry budget exhausted 🚨: not retrying '%s'", baseURL)
+			diags.AddWarning("Retry budget exhausted", fmt.Sprintf("The request to '%s' was not retried, because the retry_budget of all data sources is exhausted.", baseURL))
+			return result, hint, diags
+		}
+
+		if hint.after > 0 {
+			// The other data sources hold back their requests to this host as well, see fetchIP.
+			log.Printf("retrying 🔁: attempt %d of %d after the Retry-After of %s", attempt+1, maxRetries, hint.after)
+			p.retryAfter.delay(baseURL.Host, time.Now().Add(hint.after))
+			continue
+		}
+
+		delay := retryDelay(attempt, p.retryMinDelay, p.retryMaxDelay)
+		log.Printf("retrying 🔁: attempt %d of %d in %s", attempt+1, maxRetries, delay)
+		select {
+		case <-ctx.Done():
+			return result, hint, diags
+		case <-time.After(delay):
+		}
+	}
+}
+
+// fetchIP requests the information from the IP information provider at baseURL.
+// The returned retryHint tells whether and when a failed request may be retried.
+func fetchIP(ctx context.Context, p *ProviderModel, client *http.Client, baseURL *url.URL, query url.Values) (*ipFetchResult, retryHint, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	result := &ipFetchResult{providerURL: baseURL.String()}
+
+	requestURL := url.URL{
+		Scheme:     baseURL.Scheme,
+		Opaque:     baseURL.Opaque,
+		User:       baseURL.User,
+		Host:       baseURL.Host,
+		Path:       path.Join(baseURL.Path, "json"),
+		ForceQuery: baseURL.ForceQuery,
+		RawQuery:   baseURL.RawQuery,
+		Fragment:   baseURL.Fragment,
+	}
+	if baseURL.Scheme == "unix" {
+		requestURL = unixSocketURL(baseURL.Path)
+		requestURL.Path = "/json"
+		requestURL.RawQuery = baseURL.RawQuery
+	}
+	if len(query) > 0 {
+		values := requestURL.Query()
+		for key, value := range query {
+			values[key] = value
+		}
+		requestURL.RawQuery = values.Encode()
+	}
+	result.requestURL = requestURL.String()
+	if baseURL.Scheme == "unix" {
+		result.requestURL = baseURL.String()
+	}
+
+	log.Printf("got to prepare request ✅: %s", result.requestURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
+	if err != nil {
+		log.Printf("HTTP Client Creation Error 🚨: %s", err)
+		diags.AddError("Error preparing the HTTP request", fmt.Sprintf("There was an error when preparing the HTTP client with the url '%s': %s", result.requestURL, err))
+		return nil, retryHint{}, diags
+	}
+	if baseURL.Scheme == "unix" {
+		httpReq.Host = p.unixSocketHost
+	}
+
+	userAgent := p.nextUserAgent()
+	httpReq.Header.Set("User-Agent", userAgent)
+	if p.APIToken.Value != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.APIToken.Value)
+	}
+	if !p.Username.Null {
+		httpReq.SetBasicAuth(p.Username.Value, p.Password.Value)
+	}
+	for name, value := range p.headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	if p.requestIDHeader != "" {
+		result.requestID, err = newRequestID()
+		if err != nil {
+			log.Printf("Request ID generation error 🚨: %s", err)
+			diags.AddError("Error generating the request id", fmt.Sprintf("There was an error when generating the id for the '%s' header: %s", p.requestIDHeader, err))
+			return nil, retryHint{}, diags
+		}
+
+		httpReq.Header.Set(p.requestIDHeader, result.requestID)
+		log.Printf("got request id ✅: %s: %s", p.requestIDHeader, result.requestID)
+	}
+
+	log.Printf("got to send request ✅: %s", userAgent)
+
+	if p.rateLimiter.Tokens() < 1 {
+		log.Printf("the rate limit may be triggered ⏳")
+	}
+
+	err = p.retryAfter.wait(ctx, baseURL.Host)
+	if err != nil {
+		log.Printf("Retry-After error 🚨: %s", err)
+		diags.AddError("Error waiting for the Retry-After", fmt.Sprintf("There was an error while waiting for the time requested by the Retry-After header of the IP information provider: %s", err))
+		return nil, retryHint{}, diags
+	}
+
+	// The quota of the host may be lowered by throttle, so the wait is only bounded by the lookup, see total_timeout.
+	err = p.hostQuotas.wait(ctx, baseURL.Host)
+	if err == nil {
+		err = p.rateLimiter.Wait(ctx)
+	}
+	if err != nil {
+		log.Printf("Rate limiter error 🚨: %s", err)
+		diags.AddError("Error waiting for rate limit", fmt.Sprintf("There was an error while awaiting a slot from the rate limiter: %s", err))
+		return nil, retryHint{}, diags
+	}
+
+	// The local address of a connection to a proxy tells nothing about the connection to the IP information provider.
+	proxyURL, _ := p.proxy(httpReq)
+	proxied := proxyURL != nil
+
+	// The deadline covers reading the response body as well.
+	requestCtx, cancelRequest := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancelRequest()
+
+	// Dual-stack dialing may connect to multiple addresses concurrently, and the request is written and read concurrently.
+	var connectMutex sync.Mutex
+	connectStarts := map[string]time.Time{}
+	var wroteRequest time.Time
+	requestCtx = httptrace.WithClientTrace(requestCtx, &httptrace.ClientTrace{
+		ConnectStart: func(_, addr string) {
+			connectMutex.Lock()
+			defer connectMutex.Unlock()
+			connectStarts[addr] = time.Now()
+		},
+		ConnectDone: func(_, addr string, err error) {
+			connectMutex.Lock()
+			defer connectMutex.Unlock()
+			if err == nil && result.latency == 0 {
+				result.latency = time.Since(connectStarts[addr])
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if addr, ok := info.Conn.LocalAddr().(*net.TCPAddr); ok && !proxied {
+				result.localIP, _ = netaddr.FromStdIP(addr.IP)
+				log.Printf("got connection 🔌: LocalAddr: '%s'", addr)
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			connectMutex.Lock()
+			defer connectMutex.Unlock()
+			wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			connectMutex.Lock()
+			defer connectMutex.Unlock()
+			// A reused connection has no handshake, so the time to the first byte of the response is used instead.
+			// Unlike the handshake, it includes the time the IP information provider takes to process the request.
+			if result.latency == 0 && !wroteRequest.IsZero() {
+				result.latency = time.Since(wroteRequest)
+			}
+		},
+	})
+
+	requestStart := time.Now()
+	result.checkedAt = requestStart
+	httpResp, err := client.Do(httpReq.WithContext(requestCtx))
+	if err != nil {
+		log.Printf("HTTP client error 🚨: %s", err)
+		diags.AddError("Error fetching information from the IP information provider", withRemediation(classifyError(err), withRetryability(isTransientError(err), fmt.Sprintf("There was an error when contacting '%s': %s", result.requestURL, err))))
+		return nil, retryHint{transient: isTransientError(err), noRoute: isNoRouteError(err)}, diags
+	}
+	defer httpResp.Body.Close()
+
+	log.Printf("got to response ✅")
+	throttle(p, baseURL.Host, httpResp.Header, time.Now())
+
+	if httpResp.StatusCode != http.StatusOK {
+		log.Printf("HTTP Request Error 🚨: %d %s", httpResp.StatusCode, httpResp.Status)
+		class := errorClassUnknown
+		if httpResp.StatusCode == http.StatusTooManyRequests {
+			class = errorClassRateLimited
+		}
+		// Client errors persist, except for too many requests.
+		transient := httpResp.StatusCode >= http.StatusInternalServerError || httpResp.StatusCode == http.StatusTooManyRequests
+		diags.AddError("Error in response from the IP information provider", withRemediation(class, withRetryability(transient, fmt.Sprintf("The IP information provider responded with the status code %d '%s'", httpResp.StatusCode, httpResp.Status))))
+		hint := retryHint{transient: transient}
+		if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode == http.StatusServiceUnavailable {
+			if after, ok := parseRetryAfter(httpResp.Header.Get("Retry-After"), time.Now()); ok {
+				log.Printf("got Retry-After ⏳: %s", after)
+				hint = retryHint{transient: true, after: after}
+			}
+		}
+		return nil, hint, diags
+	}
+
+	log.Printf("got to reading ✅")
+
+	result.body, err = readLimited(httpResp.Body, p.maxResponseBytes)
+	if err != nil && !errors.Is(err, errResponseTooLarge) {
+		log.Printf("HTTP read error 🚨: %s", err)
+		diags.AddError("Error reading the response from the IP information provider", withRemediation(classifyError(err), withRetryability(isTransientError(err), fmt.Sprintf("There was an error when reading the response from '%s': %s", result.requestURL, err))))
+		return nil, retryHint{transient: isTransientError(err), noRoute: isNoRouteError(err)}, diags
+	}
+	if err != nil {
+		log.Printf("HTTP response too large 🚨: more than %d bytes", p.maxResponseBytes)
+		diags.AddError("Response of the IP information provider too large", withRetryability(false, fmt.Sprintf("The response from '%s' is larger than the max_response_bytes of %d bytes.", result.requestURL, p.maxResponseBytes)))
+		return nil, retryHint{}, diags
+	}
+
+	if mediaType, ok := checkContentType(httpResp.Header.Get("Content-Type"), p.expectedContentTypes); !ok {
+		log.Printf("Unexpected Content-Type 🚨: %s", mediaType)
+		diags.AddError("Unexpected response from the IP information provider", withRemediation(errorClassParse, withRetryability(false, fmt.Sprintf("The response from '%s' has the Content-Type '%s', which is not one of '%s', see expected_content_types. The response starts with: %s", result.requestURL, mediaType, strings.Join(p.expectedContentTypes, "', '"), bodySnippet(result.body)))))
+		return nil, retryHint{}, diags
+	}
+
+	payload, err := decodePayload(bytes.NewReader(result.body), p.envelopePath)
+	if err != nil {
+		log.Printf("JSON decode error 🚨: %s", err)
+		diags.AddError("Error parsing the response from the IP information provider", withRemediation(errorClassParse, withRetryability(false, fmt.Sprintf("There was an error when parsing the response from the IP information provider: %s", err))))
+		return nil, retryHint{}, diags
+	}
+
+	mismatches := validateResponseSchema(payload, p.responseSchema)
+	for _, mismatch := range mismatches {
+		log.Printf("Response schema mismatch 🚨: %s", mismatch)
+		diags.AddError("Unexpected response from the IP information provider", withRetryability(false, fmt.Sprintf("The response from '%s' does not conform to the response_schema: %s", result.requestURL, mismatch)))
+	}
+	if len(mismatches) > 0 {
+		return nil, retryHint{}, diags
+	}
+
+	result.respData, err = newIPResponse(payload)
+	if err != nil {
+		log.Printf("JSON decode error 🚨: %s", err)
+		diags.AddError("Error parsing the response from the IP information provider", withRemediation(errorClassParse, withRetryability(false, fmt.Sprintf("There was an error when parsing the response from the IP information provider: %s", err))))
+		return nil, retryHint{}, diags
+	}
+
+	result.responseTime = time.Since(requestStart)
+	if p.latencyWarnThreshold > 0 && result.responseTime > p.latencyWarnThreshold {
+		log.Printf("slow response ⏳: %s", result.responseTime)
+		diags.AddWarning("Slow response from the IP information provider", fmt.Sprintf("The IP information provider '%s' took %s to respond, which is longer than the latency_warn_threshold of %s.", result.requestURL, result.responseTime, p.latencyWarnThreshold))
+	}
+
+	log.Printf("got to parse ip response ✅: %+v", result.respData)
+
+	result.ip, err = netaddr.ParseIP(result.respData.IP)
+	if err != nil {
+		log.Printf("IP '%s' decode error 🚨: %s", result.respData.IP, err)
+		diags.AddError("Error parsing the IP from the IP information provider", withRemediation(errorClassParse, withRetryability(false, fmt.Sprintf("There was an error when parsing the IP '%s' of the response from the IP information provider: %s", result.respData.IP, err))))
+		return nil, retryHint{}, diags
+	}
+
+	if p.geoipDatabase != nil {
+		err = p.geoipDatabase.apply(result.ip, payload)
+		if err != nil {
+			log.Printf("GeoIP lookup error 🚨: %s", err)
+			diags.AddError("Error looking up the IP in the GeoIP database", fmt.Sprintf("The IP '%s' could not be looked up in the geoip_database_path: %s", result.ip, err))
+			return nil, retryHint{}, diags
+		}
+
+		result.respData, err = newIPResponse(payload)
+		if err != nil {
+			log.Printf("JSON decode error 🚨: %s", err)
+			diags.AddError("Error parsing the response from the IP information provider", withRemediation(errorClassParse, withRetryability(false, fmt.Sprintf("There was an error when parsing the response from the IP information provider: %s", err))))
+			return nil, retryHint{}, diags
+		}
+	}
+
+	return result, retryHint{}, diags
+}
+
+// checkLookedUpIP reports an error, if the IP information provider returned the information of another IP than the
+// requested one, e.g. because it ignores the `ip` query parameter and describes the IP of the client instead.
+func checkLookedUpIP(result *ipFetchResult, ip netaddr.IP) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if result.ip.Unmap() != ip.Unmap() {
+		log.Printf("IP mismatch 🚨: requested '%s', got '%s'", ip, result.ip)
+		diags.AddError("Unexpected response from the IP information provider", withRetryability(false, fmt.Sprintf("The response from '%s' describes the IP '%s' instead of the requested IP '%s'. Check that the IP information provider supports looking up arbitrary IPs.", result.requestURL, result.ip, ip)))
+	}
+	return diags
+}
+
+// checkContentType returns the media type of the Content-Type and whether it is one of the expected media types.
+// A missing Content-Type is always accepted, as is any Content-Type if no media types are expected.
+func checkContentType(contentType string, expected []string) (string, bool) {
+	if contentType == "" || len(expected) == 0 {
+		return contentType, true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType, false
+	}
+	for _, expectedMediaType := range expected {
+		if mediaType == expectedMediaType {
+			return mediaType, true
+		}
+		// Structured syntax suffixes, e.g. application/problem+json, are JSON as well.
+		if expectedMediaType == "application/json" && strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json") {
+			return mediaType, true
+		}
+	}
+	return mediaType, false
+}
+
+// bodySnippet returns the beginning of a response body for diagnostics.
+func bodySnippet(body []byte) string {
+	const maxLength = 200
+
+	snippet := strings.ToValidUTF8(string(body), "�")
+	if runes := []rune(snippet); len(runes) > maxLength {
+		snippet = string(runes[:maxLength]) + "…"
+	}
+	return strconv.Quote(snippet)
+}
+
+// errResponseTooLarge is returned by readLimited for responses, which are larger than the limit.
+var errResponseTooLarge = errors.New("the response is larger than the max_response_bytes")
+
+// readLimited reads the whole response, unless it's larger than the limit, see max_response_bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	// One byte more than allowed is read to detect responses, which are too large.
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes", errResponseTooLarge, limit)
+	}
+	return body, nil
+}
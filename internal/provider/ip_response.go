@@ -1,7 +1,11 @@
 package provider
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
 )
 
 type IPResponse struct {
@@ -14,15 +18,92 @@ type IPResponse struct {
 	RegionCode string      `json:"region_code,omitempty"`
 	ZIPCode    string      `json:"zip_code,omitempty"`
 	City       string      `json:"city,omitempty"`
-	Latitude   float32     `json:"latitude,omitempty"`
-	Longitude  float32     `json:"longitude,omitempty"`
+	Latitude   json.Number `json:"latitude,omitempty"`
+	Longitude  json.Number `json:"longitude,omitempty"`
 	TimeZone   string      `json:"time_zone,omitempty"`
 	ASN        string      `json:"asn,omitempty"`
 	ASNOrg     string      `json:"asn_org,omitempty"`
+	Hostname   string      `json:"hostname,omitempty"`
 	UserAgent  struct {
 		Product  string `json:"product,omitempty"`
 		Version  string `json:"version,omitempty"`
 		Comment  string `json:"comment,omitempty"`
 		RAWValue string `json:"raw_value,omitempty"`
 	} `json:"user_agent"`
+
+	// payload is the generic representation of the response.
+	payload map[string]interface{}
+}
+
+// has reports whether the field was present in the response, even if it was empty.
+func (r *IPResponse) has(field string) bool {
+	_, ok := r.payload[field]
+	return ok
+}
+
+// decodeIPResponse decodes the JSON response of the IP information provider.
+// Numbers are kept in their textual representation, so that the coordinates don't lose any precision.
+// If envelopePath is set, the payload is expected within the (nested) object at that path, e.g. `data` or `result.data`.
+func decodeIPResponse(reader io.Reader, envelopePath string) (*IPResponse, error) {
+	payload, err := decodePayload(reader, envelopePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIPResponse(payload)
+}
+
+// decodePayload decodes the JSON response of the IP information provider into its generic representation.
+func decodePayload(reader io.Reader, envelopePath string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	var payload map[string]interface{}
+	err := decoder.Decode(&payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if envelopePath != "" {
+		return unwrapEnvelope(payload, envelopePath)
+	}
+	return payload, nil
+}
+
+// newIPResponse maps the generic representation of the response onto the fields of IPResponse.
+func newIPResponse(payload map[string]interface{}) (*IPResponse, error) {
+	// The payload is encoded again, so that the regular field mappings of IPResponse apply.
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	respData := new(IPResponse)
+	decoder := json.NewDecoder(bytes.NewReader(rawPayload))
+	decoder.UseNumber()
+	err = decoder.Decode(respData)
+	if err != nil {
+		return nil, err
+	}
+
+	respData.payload = payload
+	return respData, nil
+}
+
+// unwrapEnvelope returns the object found at the dot-separated envelopePath.
+func unwrapEnvelope(envelope map[string]interface{}, envelopePath string) (map[string]interface{}, error) {
+	current := envelope
+	for _, key := range strings.Split(envelopePath, ".") {
+		value, ok := current[key]
+		if !ok {
+			return nil, fmt.Errorf("the envelope key '%s' of the envelope_path '%s' is missing in the response", key, envelopePath)
+		}
+
+		current, ok = value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the envelope key '%s' of the envelope_path '%s' is not a JSON object but %T", key, envelopePath, value)
+		}
+	}
+
+	return current, nil
 }
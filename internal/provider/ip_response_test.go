@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeIPResponseKeepsCoordinatePrecision(t *testing.T) {
+	respData, err := decodeIPResponse(strings.NewReader(`{"ip":"203.0.113.4","latitude":47.376886123456789,"longitude":8.541694123456789}`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	latitude, latitudeExact := coordinateValues(respData.Latitude)
+	if latitudeExact.Value != "47.376886123456789" {
+		t.Errorf("expected exact latitude '47.376886123456789', got '%s'", latitudeExact.Value)
+	}
+	if latitude.Value != 47.376886123456789 {
+		t.Errorf("expected latitude 47.376886123456789, got %v", latitude.Value)
+	}
+
+	_, longitudeExact := coordinateValues(respData.Longitude)
+	if longitudeExact.Value != "8.541694123456789" {
+		t.Errorf("expected exact longitude '8.541694123456789', got '%s'", longitudeExact.Value)
+	}
+}
+
+func TestCoordinateValuesAbsent(t *testing.T) {
+	respData, err := decodeIPResponse(strings.NewReader(`{"ip":"203.0.113.4"}`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	latitude, latitudeExact := coordinateValues(respData.Latitude)
+	if !latitude.Null || !latitudeExact.Null {
+		t.Errorf("expected null coordinates, got %v and %v", latitude, latitudeExact)
+	}
+}
+
+func TestDecodeIPResponseEnvelope(t *testing.T) {
+	tests := map[string]struct {
+		body         string
+		envelopePath string
+	}{
+		"no envelope":     {`{"ip":"203.0.113.4","asn":"AS64496","latitude":47.376886123456789}`, ""},
+		"data envelope":   {`{"status":"ok","data":{"ip":"203.0.113.4","asn":"AS64496","latitude":47.376886123456789}}`, "data"},
+		"nested envelope": {`{"result":{"data":{"ip":"203.0.113.4","asn":"AS64496","latitude":47.376886123456789}}}`, "result.data"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			respData, err := decodeIPResponse(strings.NewReader(test.body), test.envelopePath)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if respData.IP != "203.0.113.4" || respData.ASN != "AS64496" {
+				t.Errorf("unexpected response: %+v", respData)
+			}
+			if respData.Latitude.String() != "47.376886123456789" {
+				t.Errorf("expected exact latitude '47.376886123456789', got '%s'", respData.Latitude)
+			}
+		})
+	}
+}
+
+func TestDecodeIPResponseEnvelopeMissing(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected string
+	}{
+		"absent":     {`{"ip":"203.0.113.4"}`, "the envelope key 'data' of the envelope_path 'data' is missing in the response"},
+		"not object": {`{"data":"203.0.113.4"}`, "the envelope key 'data' of the envelope_path 'data' is not a JSON object but string"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeIPResponse(strings.NewReader(test.body), "data")
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("expected error '%s', got '%v'", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestDecodeIPResponseFields(t *testing.T) {
+	respData, err := decodeIPResponse(strings.NewReader(`{"ip":"203.0.113.4","country":""}`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !respData.has("country") {
+		t.Errorf("expected the empty country to be present")
+	}
+	if respData.has("city") {
+		t.Errorf("expected the city to be absent")
+	}
+}
+
+func TestDecimalValueKeepsPrecision(t *testing.T) {
+	respData, err := decodeIPResponse(strings.NewReader(`{"ip":"2001:db8::1","ip_decimal":42540766411282592856903984951653826561}`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	decimal := decimalValue(respData.IPDecimal)
+	if decimal.Null || decimal.Value.Text('f', -1) != "42540766411282592856903984951653826561" {
+		t.Errorf("expected ip_decimal '42540766411282592856903984951653826561', got %v", decimal)
+	}
+
+	if absent := decimalValue(""); !absent.Null {
+		t.Errorf("expected a null ip_decimal, got %v", absent)
+	}
+}
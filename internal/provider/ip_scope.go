@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"inet.af/netaddr"
+)
+
+const IPScopeGlobal = "global"
+const IPScopePrivate = "private"
+const IPScopeUniqueLocal = "unique-local"
+const IPScopeLinkLocal = "link-local"
+const IPScopeLoopback = "loopback"
+const IPScopeMulticast = "multicast"
+const IPScopeUnspecified = "unspecified"
+
+// ipScope describes the scope of an IP with a single value.
+// Private IPv4 (RFC 1918) and IPv6 (RFC 4193) addresses are distinguished as `private` and `unique-local` respectively.
+func ipScope(ip netaddr.IP) string {
+	ip = ip.Unmap()
+
+	switch {
+	case !ip.IsValid():
+		return IPUnknown
+	case ip.IsUnspecified():
+		return IPScopeUnspecified
+	case ip.IsLoopback():
+		return IPScopeLoopback
+	case ip.IsMulticast():
+		return IPScopeMulticast
+	case ip.IsLinkLocalUnicast():
+		return IPScopeLinkLocal
+	case ip.IsPrivate() && ip.Is6():
+		return IPScopeUniqueLocal
+	case ip.IsPrivate():
+		return IPScopePrivate
+	case ip.IsGlobalUnicast():
+		return IPScopeGlobal
+	default:
+		return IPUnknown
+	}
+}
+
+// cgnatPrefix is the shared address space for carrier-grade NAT (RFC 6598).
+var cgnatPrefix = netaddr.MustParseIPPrefix("100.64.0.0/10")
+
+// bogonPrefixes are the ranges, which must not appear on the internet, because they are reserved or not allocated (RFC 6890).
+var bogonPrefixes = []netaddr.IPPrefix{
+	netaddr.MustParseIPPrefix("0.0.0.0/8"),
+	netaddr.MustParseIPPrefix("10.0.0.0/8"),
+	netaddr.MustParseIPPrefix("100.64.0.0/10"),
+	netaddr.MustParseIPPrefix("127.0.0.0/8"),
+	netaddr.MustParseIPPrefix("169.254.0.0/16"),
+	netaddr.MustParseIPPrefix("172.16.0.0/12"),
+	netaddr.MustParseIPPrefix("192.0.0.0/24"),
+	netaddr.MustParseIPPrefix("192.0.2.0/24"),
+	netaddr.MustParseIPPrefix("192.168.0.0/16"),
+	netaddr.MustParseIPPrefix("198.18.0.0/15"),
+	netaddr.MustParseIPPrefix("198.51.100.0/24"),
+	netaddr.MustParseIPPrefix("203.0.113.0/24"),
+	netaddr.MustParseIPPrefix("224.0.0.0/4"),
+	netaddr.MustParseIPPrefix("240.0.0.0/4"),
+	netaddr.MustParseIPPrefix("100::/64"),
+	netaddr.MustParseIPPrefix("2001:2::/48"),
+	netaddr.MustParseIPPrefix("2001:10::/28"),
+	netaddr.MustParseIPPrefix("2001:db8::/32"),
+	netaddr.MustParseIPPrefix("3ffe::/16"),
+}
+
+// ipv6GlobalUnicast is the only range of IPv6, which is allocated for global unicast.
+var ipv6GlobalUnicast = netaddr.MustParseIPPrefix("2000::/3")
+
+// ipIsPrivate reports whether the IP is private, i.e. in RFC 1918 (IPv4) or RFC 4193 (IPv6).
+func ipIsPrivate(ip netaddr.IP) bool {
+	return ip.Unmap().IsPrivate()
+}
+
+// ipIsCGNAT reports whether the IP is in the shared address space of carrier-grade NAT (RFC 6598).
+func ipIsCGNAT(ip netaddr.IP) bool {
+	return cgnatPrefix.Contains(ip.Unmap())
+}
+
+// ipIsBogon reports whether the IP is reserved or not allocated, i.e. whether it must not appear as a public IP.
+func ipIsBogon(ip netaddr.IP) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() {
+		return false
+	}
+	if ip.Is6() && !ipv6GlobalUnicast.Contains(ip) {
+		return true
+	}
+	for _, prefix := range bogonPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
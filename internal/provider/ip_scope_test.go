@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"testing"
+
+	"inet.af/netaddr"
+)
+
+func TestIPScope(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.4":     IPScopeGlobal,
+		"2001:db8::1":     IPScopeGlobal,
+		"10.1.2.3":        IPScopePrivate,
+		"192.168.1.1":     IPScopePrivate,
+		"fd00::1":         IPScopeUniqueLocal,
+		"169.254.1.1":     IPScopeLinkLocal,
+		"fe80::1":         IPScopeLinkLocal,
+		"127.0.0.1":       IPScopeLoopback,
+		"::1":             IPScopeLoopback,
+		"224.0.0.1":       IPScopeMulticast,
+		"ff02::1":         IPScopeMulticast,
+		"0.0.0.0":         IPScopeUnspecified,
+		"::":              IPScopeUnspecified,
+		"::ffff:10.1.2.3": IPScopePrivate,
+		"255.255.255.255": IPUnknown,
+	}
+
+	for address, expected := range tests {
+		t.Run(address, func(t *testing.T) {
+			scope := ipScope(netaddr.MustParseIP(address))
+			if scope != expected {
+				t.Errorf("expected scope '%s' for '%s', got '%s'", expected, address, scope)
+			}
+		})
+	}
+}
+
+func TestIPClassification(t *testing.T) {
+	tests := map[string]struct {
+		private bool
+		cgnat   bool
+		bogon   bool
+	}{
+		"8.8.8.8":              {false, false, false},
+		"2a00:1450::1":         {false, false, false},
+		"10.1.2.3":             {true, false, true},
+		"172.16.0.1":           {true, false, true},
+		"192.168.1.1":          {true, false, true},
+		"::ffff:192.168.1.1":   {true, false, true},
+		"fd00::1":              {true, false, true},
+		"100.64.0.1":           {false, true, true},
+		"100.127.255.254":      {false, true, true},
+		"100.128.0.1":          {false, false, false},
+		"203.0.113.4":          {false, false, true},
+		"2001:db8::1":          {false, false, true},
+		"240.0.0.1":            {false, false, true},
+		"fe80::1":              {false, false, true},
+		"4000::1":              {false, false, true},
+		"2001:470:1:2::3":      {false, false, false},
+		"2001:10:ffff::1":      {false, false, true},
+		"::ffff:100.64.12.34":  {false, true, true},
+		"3ffe:1234:5678::9abc": {false, false, true},
+	}
+
+	for address, expected := range tests {
+		t.Run(address, func(t *testing.T) {
+			ip := netaddr.MustParseIP(address)
+			if ipIsPrivate(ip) != expected.private || ipIsCGNAT(ip) != expected.cgnat || ipIsBogon(ip) != expected.bogon {
+				t.Errorf("expected %+v for '%s', got private: %t, cgnat: %t, bogon: %t", expected, address, ipIsPrivate(ip), ipIsCGNAT(ip), ipIsBogon(ip))
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type LocalAddressesDataSource struct {
+	provider *ProviderModel
+	// interfaces lists the local network interfaces.
+	interfaces func() ([]net.Interface, error)
+	// prefixes lists the addresses of an interface.
+	prefixes func(iface *net.Interface) ([]netaddr.IPPrefix, error)
+}
+
+func NewLocalAddressesDataSource() datasource.DataSource {
+	return &LocalAddressesDataSource{interfaces: net.Interfaces, prefixes: interfacePrefixes}
+}
+
+func (d LocalAddressesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_addresses"
+}
+
+func (d LocalAddressesDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Lists the local IPs of all network interfaces, which are up, except the loopback interfaces. Useful to iterate over the candidates for `source_ip` of `publicip_address`. No request is sent.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"include_link_local": {
+				MarkdownDescription: "Whether link-local IPs, e.g. `fe80::1`, are included. They can't be used to reach the internet. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"ipv4": {
+				MarkdownDescription: "The IPv4 of all interfaces.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"ipv6": {
+				MarkdownDescription: "The IPv6 of all interfaces.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"interfaces": {
+				MarkdownDescription: "The IPs by interface. Interfaces without IPs are omitted.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						MarkdownDescription: "The name of the interface, e.g. `eth0`.",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"ipv4": {
+						MarkdownDescription: "The IPv4 of the interface.",
+						Computed:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"ipv6": {
+						MarkdownDescription: "The IPv6 of the interface.",
+						Computed:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *LocalAddressesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type LocalAddressesDataSourceModel struct {
+	ID               types.String                 `tfsdk:"id"`
+	IncludeLinkLocal types.Bool                   `tfsdk:"include_link_local"`
+	IPv4             []string                     `tfsdk:"ipv4"`
+	IPv6             []string                     `tfsdk:"ipv6"`
+	Interfaces       []LocalInterfaceAddressModel `tfsdk:"interfaces"`
+}
+
+type LocalInterfaceAddressModel struct {
+	Name string   `tfsdk:"name"`
+	IPv4 []string `tfsdk:"ipv4"`
+	IPv6 []string `tfsdk:"ipv6"`
+}
+
+func (d LocalAddressesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data LocalAddressesDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ifaces, err := d.interfaces()
+	if err != nil {
+		log.Printf("Interface error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error listing the interfaces", fmt.Sprintf("The network interfaces could not be listed: %s", err))
+		return
+	}
+
+	data.IPv4 = []string{}
+	data.IPv6 = []string{}
+	data.Interfaces = []LocalInterfaceAddressModel{}
+	for i := range ifaces {
+		iface := &ifaces[i]
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		prefixes, err := d.prefixes(iface)
+		if err != nil {
+			log.Printf("Interface error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error listing the addresses", fmt.Sprintf("The addresses of the interface '%s' could not be listed: %s", iface.Name, err))
+			return
+		}
+
+		addresses := LocalInterfaceAddressModel{Name: iface.Name, IPv4: []string{}, IPv6: []string{}}
+		for _, prefix := range prefixes {
+			ip := prefix.IP()
+			scope := ipScope(ip)
+			if scope == IPScopeLoopback || (scope == IPScopeLinkLocal && !data.IncludeLinkLocal.Value) {
+				continue
+			}
+			if ip.Is4() {
+				addresses.IPv4 = append(addresses.IPv4, ip.String())
+			} else {
+				addresses.IPv6 = append(addresses.IPv6, ip.String())
+			}
+		}
+		if len(addresses.IPv4)+len(addresses.IPv6) == 0 {
+			continue
+		}
+
+		data.IPv4 = append(data.IPv4, addresses.IPv4...)
+		data.IPv6 = append(data.IPv6, addresses.IPv6...)
+		data.Interfaces = append(data.Interfaces, addresses)
+	}
+
+	data.ID = types.String{Value: strings.Join(append(append([]string{}, data.IPv4...), data.IPv6...), ",")}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
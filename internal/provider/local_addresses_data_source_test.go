@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+func testLocalAddressesDataSource() datasource.DataSource {
+	prefixes := map[string][]string{
+		"lo":   {"127.0.0.1/8", "::1/128"},
+		"eth0": {"192.168.1.10/24", "2001:db8::10/64", "fe80::10/64"},
+		"eth1": {"10.0.0.10/8"},
+		"wg0":  {"fe80::20/64"},
+	}
+
+	return &LocalAddressesDataSource{
+		interfaces: func() ([]net.Interface, error) {
+			return []net.Interface{
+				{Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+				{Name: "eth0", Flags: net.FlagUp},
+				{Name: "eth1"},
+				{Name: "wg0", Flags: net.FlagUp},
+			}, nil
+		},
+		prefixes: func(iface *net.Interface) ([]netaddr.IPPrefix, error) {
+			var result []netaddr.IPPrefix
+			for _, prefix := range prefixes[iface.Name] {
+				result = append(result, netaddr.MustParseIPPrefix(prefix))
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestLocalAddressesDataSource(t *testing.T) {
+	resp := testReadDataSource(t, testLocalAddressesDataSource, nil, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data LocalAddressesDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if len(data.IPv4) != 1 || data.IPv4[0] != "192.168.1.10" || len(data.IPv6) != 1 || data.IPv6[0] != "2001:db8::10" {
+		t.Errorf("unexpected IPs: %+v", data)
+	}
+	if len(data.Interfaces) != 1 || data.Interfaces[0].Name != "eth0" {
+		t.Errorf("expected only eth0: %+v", data.Interfaces)
+	}
+}
+
+func TestLocalAddressesDataSourceLinkLocal(t *testing.T) {
+	resp := testReadDataSource(t, testLocalAddressesDataSource, nil, map[string]tftypes.Value{
+		"include_link_local": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data LocalAddressesDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if len(data.Interfaces) != 2 || data.Interfaces[1].Name != "wg0" || len(data.Interfaces[0].IPv6) != 2 || len(data.IPv6) != 3 {
+		t.Errorf("expected the link-local IPs: %+v", data)
+	}
+}
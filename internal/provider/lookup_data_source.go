@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type LookupDataSource struct {
+	provider *ProviderModel
+}
+
+func NewLookupDataSource() datasource.DataSource {
+	return &LookupDataSource{}
+}
+
+func (d LookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lookup"
+}
+
+func (d LookupDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Looks up the information about an arbitrary IP at the IP information provider.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up.",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"scope": {
+				MarkdownDescription: fmt.Sprintf("The scope of the IP. Expected values: '%s', '%s', '%s', '%s', '%s', '%s', '%s', '%s'", IPScopeGlobal, IPScopePrivate, IPScopeUniqueLocal, IPScopeLinkLocal, IPScopeLoopback, IPScopeMulticast, IPScopeUnspecified, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"is_private": {
+				MarkdownDescription: "`true` if the IP is private, i.e. in RFC 1918 (IPv4) or RFC 4193 (IPv6).",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_cgnat": {
+				MarkdownDescription: "`true` if the IP is in the shared address space of carrier-grade NAT, i.e. `100.64.0.0/10` (RFC 6598).",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_bogon": {
+				MarkdownDescription: "`true` if the IP is reserved or not allocated, e.g. private, CGNAT or documentation ranges, i.e. if it is not a valid public IP.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"hostname": {
+				MarkdownDescription: "The hostname of the IP, i.e. its reverse DNS (PTR) name, as returned by the IP information provider. `null` if the IP has none.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"asn_id": {
+				MarkdownDescription: "The ASN as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"asn_org": {
+				MarkdownDescription: "The organisation to which the ASN is registered to as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country": {
+				MarkdownDescription: "The country as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country_iso": {
+				MarkdownDescription: "The ISO code of the country as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region_name": {
+				MarkdownDescription: "The name of the region as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"region_code": {
+				MarkdownDescription: "The code of the region as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"city": {
+				MarkdownDescription: "The city as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"zip_code": {
+				MarkdownDescription: "The ZIP code as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"latitude": {
+				MarkdownDescription: "The latitude as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"longitude": {
+				MarkdownDescription: "The longitude as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.Float64Type,
+			},
+			"provider_url_used": {
+				MarkdownDescription: "The URL of the IP information provider, which provided the information.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"time_zone": {
+				MarkdownDescription: "The time zone as returned by the IP information provider, e.g. `Europe/Zurich`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *LookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type LookupDataSourceModel struct {
+	ID              types.String  `tfsdk:"id"`
+	IP              types.String  `tfsdk:"ip"`
+	IPVersion       types.String  `tfsdk:"ip_version"`
+	Scope           types.String  `tfsdk:"scope"`
+	IsPrivate       types.Bool    `tfsdk:"is_private"`
+	IsCGNAT         types.Bool    `tfsdk:"is_cgnat"`
+	IsBogon         types.Bool    `tfsdk:"is_bogon"`
+	Hostname        types.String  `tfsdk:"hostname"`
+	ASNID           types.String  `tfsdk:"asn_id"`
+	ASNOrg          types.String  `tfsdk:"asn_org"`
+	Country         types.String  `tfsdk:"country"`
+	CountryISO      types.String  `tfsdk:"country_iso"`
+	RegionName      types.String  `tfsdk:"region_name"`
+	RegionCode      types.String  `tfsdk:"region_code"`
+	City            types.String  `tfsdk:"city"`
+	ZIPCode         types.String  `tfsdk:"zip_code"`
+	Latitude        types.Float64 `tfsdk:"latitude"`
+	Longitude       types.Float64 `tfsdk:"longitude"`
+	TimeZone        types.String  `tfsdk:"time_zone"`
+	ProviderURLUsed types.String  `tfsdk:"provider_url_used"`
+}
+
+func (d LookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data LookupDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, err := netaddr.ParseIP(data.IP.Value)
+	if err != nil {
+		log.Printf("Could not parse IP '%s' 🚨: %s", data.IP.Value, err)
+		resp.Diagnostics.AddError("Invalid IP", fmt.Sprintf("The ip '%s' could not be parsed: %s", data.IP.Value, err))
+		return
+	}
+
+	client := newHTTPClient(d.provider, "tcp", netaddr.IP{})
+	result, diags := fetchIPFromProviders(ctx, d.provider, client, url.Values{"ip": {ip.String()}})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(checkLookedUpIP(result, ip)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	respData := result.respData
+
+	data.ID = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+	data.Scope = types.String{Value: ipScope(ip)}
+	data.IsPrivate = types.Bool{Value: ipIsPrivate(ip)}
+	data.IsCGNAT = types.Bool{Value: ipIsCGNAT(ip)}
+	data.IsBogon = types.Bool{Value: ipIsBogon(ip)}
+	data.Hostname = types.String{Null: true}
+	if respData.Hostname != "" {
+		data.Hostname = types.String{Value: respData.Hostname}
+	}
+	data.ASNID = types.String{Value: respData.ASN}
+	data.ASNOrg = types.String{Value: respData.ASNOrg}
+	data.Country = types.String{Value: respData.Country}
+	data.CountryISO = types.String{Value: respData.CountryISO}
+	data.RegionName = types.String{Value: respData.RegionName}
+	data.RegionCode = types.String{Value: respData.RegionCode}
+	data.City = types.String{Value: respData.City}
+	data.ZIPCode = types.String{Value: respData.ZIPCode}
+	data.Latitude, _ = coordinateValues(respData.Latitude)
+	data.Longitude, _ = coordinateValues(respData.Longitude)
+	data.TimeZone = types.String{Value: respData.TimeZone}
+	data.ProviderURLUsed = types.String{Value: result.providerURL}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
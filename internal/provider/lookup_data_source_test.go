@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestLookupDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"ip":"%s","asn":"AS64496","asn_org":"Example","country":"Switzerland","city":"Zurich","latitude":47.3682}`, r.URL.Query().Get("ip"))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewLookupDataSource, providerData, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "2001:db8::1"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data LookupDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.ID.Value != "2001:db8::1" || data.IPVersion.Value != IPVersion6 {
+		t.Errorf("unexpected IP: %+v", data)
+	}
+	if data.ASNID.Value != "AS64496" || data.ASNOrg.Value != "Example" || data.Country.Value != "Switzerland" || data.City.Value != "Zurich" || data.Latitude.Value != 47.3682 {
+		t.Errorf("unexpected information: %+v", data)
+	}
+	if !data.Longitude.Null || !data.Hostname.Null {
+		t.Errorf("expected absent fields to be null: %+v", data)
+	}
+	if data.ProviderURLUsed.Value != server.URL {
+		t.Errorf("expected provider_url_used '%s', got '%s'", server.URL, data.ProviderURLUsed.Value)
+	}
+}
+
+func TestLookupDataSourceInvalidIP(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewLookupDataSource, providerData, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "example.com"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestLookupDataSourceIPMismatch(t *testing.T) {
+	// The IP information provider ignores the ip query parameter and returns the IP of the client.
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4","country":"Switzerland"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, NewLookupDataSource, providerData, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "2001:db8::1"),
+	})
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), "instead of the requested IP '2001:db8::1'") {
+		t.Errorf("expected the IP mismatch to be reported, got: %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+// nat64DiscoveryName only has A records, so any AAAA record is synthesized by DNS64 (RFC 7050).
+const nat64DiscoveryName = "ipv4only.arpa"
+
+// nat64WellKnownIPs are the A records of ipv4only.arpa.
+var nat64WellKnownIPs = []netaddr.IP{
+	netaddr.MustParseIP("192.0.0.170"),
+	netaddr.MustParseIP("192.0.0.171"),
+}
+
+// nat64PrefixLengths are the prefix lengths of RFC 6052, the most common first.
+var nat64PrefixLengths = []uint8{96, 64, 56, 48, 40, 32}
+
+type NAT64DataSource struct {
+	provider *ProviderModel
+	// lookupIP resolves the AAAA records of ipv4only.arpa.
+	lookupIP func(ctx context.Context, network string, host string) ([]net.IP, error)
+}
+
+func NewNAT64DataSource() datasource.DataSource {
+	return &NAT64DataSource{lookupIP: net.DefaultResolver.LookupIP}
+}
+
+func (d NAT64DataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nat64"
+}
+
+func (d NAT64DataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Detects whether the local resolver uses DNS64, i.e. whether IPv4 is reachable through NAT64, by resolving `ipv4only.arpa` (RFC 7050).",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"nat64": {
+				MarkdownDescription: "`true` if the resolver synthesizes IPv6 for IPv4, i.e. a NAT64 prefix was discovered.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"prefix": {
+				MarkdownDescription: "The NAT64 prefix, e.g. `64:ff9b::/96`. `null` if no NAT64 prefix was discovered.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"prefixes": {
+				MarkdownDescription: "All discovered NAT64 prefixes, if the network uses several.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+		},
+	}, nil
+}
+
+func (d *NAT64DataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type NAT64DataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	NAT64    types.Bool   `tfsdk:"nat64"`
+	Prefix   types.String `tfsdk:"prefix"`
+	Prefixes []string     `tfsdk:"prefixes"`
+}
+
+func (d NAT64DataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data NAT64DataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	log.Printf("looking up the NAT64 prefix 🔍: %s", nat64DiscoveryName)
+
+	ips, err := d.lookupIP(ctx, "ip6", nat64DiscoveryName)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		ips, err = nil, nil
+	}
+	if err != nil {
+		log.Printf("NAT64 error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error looking up the NAT64 prefix", fmt.Sprintf("The AAAA records of '%s' could not be looked up: %s", nat64DiscoveryName, err))
+		return
+	}
+
+	data.Prefixes = []string{}
+	seen := map[netaddr.IPPrefix]bool{}
+	for _, stdIP := range ips {
+		ip, ok := netaddr.FromStdIP(stdIP)
+		if !ok || !ip.Is6() || ip.Is4in6() {
+			continue
+		}
+		if prefix, ok := nat64Prefix(ip); ok && !seen[prefix] {
+			seen[prefix] = true
+			data.Prefixes = append(data.Prefixes, prefix.String())
+		}
+	}
+
+	data.NAT64 = types.Bool{Value: len(data.Prefixes) > 0}
+	data.Prefix = types.String{Null: true}
+	data.ID = types.String{Value: "none"}
+	if len(data.Prefixes) > 0 {
+		data.Prefix = types.String{Value: data.Prefixes[0]}
+		data.ID = types.String{Value: data.Prefixes[0]}
+	}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// nat64Prefix returns the prefix of the synthesized IP, if it embeds one of the well-known IPs of ipv4only.arpa.
+func nat64Prefix(ip netaddr.IP) (netaddr.IPPrefix, bool) {
+	for _, length := range nat64PrefixLengths {
+		embedded := nat64EmbeddedIPv4(ip, length)
+		for _, wellKnown := range nat64WellKnownIPs {
+			if embedded == wellKnown {
+				prefix, err := ip.Prefix(length)
+				return prefix, err == nil
+			}
+		}
+	}
+	return netaddr.IPPrefix{}, false
+}
+
+// nat64EmbeddedIPv4 extracts the IPv4 embedded after a prefix of the length (RFC 6052 section 2.2).
+// The bits 64 to 71 are reserved and are skipped.
+func nat64EmbeddedIPv4(ip netaddr.IP, length uint8) netaddr.IP {
+	bytes := ip.As16()
+	var ipv4 [4]byte
+	position := int(length / 8)
+	for i := range ipv4 {
+		if position == 8 {
+			position++
+		}
+		ipv4[i] = bytes[position]
+		position++
+	}
+	return netaddr.IPFrom4(ipv4)
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"inet.af/netaddr"
+)
+
+func TestNAT64Prefix(t *testing.T) {
+	// The examples of RFC 6052 section 2.4 with the well-known IPs of ipv4only.arpa.
+	tests := map[string]string{
+		"64:ff9b::c000:aa":              "64:ff9b::/96",
+		"2001:db8:c000:aa::":            "2001:db8::/32",
+		"2001:db8:1c0:0:aa::":           "2001:db8:100::/40",
+		"2001:db8:122:c000:0:ab00::":    "2001:db8:122::/48",
+		"2001:db8:122:3c0:0:aa::":       "2001:db8:122:300::/56",
+		"2001:db8:122:344:c0:0:aa00:0":  "2001:db8:122:344::/64",
+		"2001:db8:122:344::192.0.0.171": "2001:db8:122:344::/96",
+	}
+
+	for address, expected := range tests {
+		prefix, ok := nat64Prefix(netaddr.MustParseIP(address))
+		if !ok || prefix.String() != expected {
+			t.Errorf("expected '%s' for '%s', got '%s'", expected, address, prefix)
+		}
+	}
+
+	if _, ok := nat64Prefix(netaddr.MustParseIP("2001:db8::1")); ok {
+		t.Error("expected no prefix for a regular IP")
+	}
+}
+
+func TestNAT64DataSource(t *testing.T) {
+	newDataSource := func() datasource.DataSource {
+		return &NAT64DataSource{lookupIP: func(_ context.Context, network string, host string) ([]net.IP, error) {
+			if network != "ip6" || host != nat64DiscoveryName {
+				t.Errorf("unexpected lookup of '%s' over '%s'", host, network)
+			}
+			return []net.IP{net.ParseIP("64:ff9b::c000:aa"), net.ParseIP("64:ff9b::c000:ab")}, nil
+		}}
+	}
+
+	resp := testReadDataSource(t, newDataSource, nil, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data NAT64DataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if !data.NAT64.Value || data.Prefix.Value != "64:ff9b::/96" || len(data.Prefixes) != 1 {
+		t.Errorf("unexpected NAT64 prefix: %+v", data)
+	}
+}
+
+func TestNAT64DataSourceWithoutDNS64(t *testing.T) {
+	newDataSource := func() datasource.DataSource {
+		return &NAT64DataSource{lookupIP: func(_ context.Context, _ string, host string) ([]net.IP, error) {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}}
+	}
+
+	resp := testReadDataSource(t, newDataSource, nil, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data NAT64DataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.NAT64.Value || !data.Prefix.Null || len(data.Prefixes) != 0 {
+		t.Errorf("expected no NAT64: %+v", data)
+	}
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+// The NAT types as classified by RFC 3489.
+const (
+	NATTypeNone           = "none"
+	NATTypeFullCone       = "full-cone"
+	NATTypeRestricted     = "restricted"
+	NATTypePortRestricted = "port-restricted"
+	NATTypeSymmetric      = "symmetric"
+)
+
+// DefaultNATSTUNServer supports the CHANGE-REQUEST and OTHER-ADDRESS of RFC 5780, which are required for the classification.
+const DefaultNATSTUNServer = "stun.stunprotocol.org:3478"
+const DefaultNATProbeTimeout = "2s"
+
+type NATDataSource struct {
+	provider *ProviderModel
+}
+
+func NewNATDataSource() datasource.DataSource {
+	return &NATDataSource{}
+}
+
+func (d NATDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nat"
+}
+
+func (d NATDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Classifies the NAT between this host and the internet using multiple STUN binding requests (RFC 3489, RFC 5780). Useful to decide whether a relay is required.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"server": {
+				MarkdownDescription: fmt.Sprintf("The STUN server with its port. It must support RFC 5780, i.e. respond from an alternate address. Defaults to `%s`.", DefaultNATSTUNServer),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"probe_timeout": {
+				MarkdownDescription: fmt.Sprintf("How long to wait for each STUN response. As missing responses are part of the classification, this determines the duration of the read. Defaults to `%s`.", DefaultNATProbeTimeout),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"nat_type": {
+				MarkdownDescription: fmt.Sprintf("The type of the NAT. Expected values: '%s', '%s', '%s', '%s', '%s'", NATTypeNone, NATTypeFullCone, NATTypeRestricted, NATTypePortRestricted, NATTypeSymmetric),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"mapped_ip": {
+				MarkdownDescription: "The public IP as seen by the STUN server.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"mapped_port": {
+				MarkdownDescription: "The public UDP port as seen by the STUN server.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+		},
+	}, nil
+}
+
+func (d *NATDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type NATDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Server       types.String `tfsdk:"server"`
+	ProbeTimeout types.String `tfsdk:"probe_timeout"`
+	NATType      types.String `tfsdk:"nat_type"`
+	MappedIP     types.String `tfsdk:"mapped_ip"`
+	MappedPort   types.Int64  `tfsdk:"mapped_port"`
+}
+
+func (d NATDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data NATDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	server := DefaultNATSTUNServer
+	if !data.Server.Null {
+		server = data.Server.Value
+	}
+
+	probeTimeoutStr := DefaultNATProbeTimeout
+	if !data.ProbeTimeout.Null {
+		probeTimeoutStr = data.ProbeTimeout.Value
+	}
+	probeTimeout, err := time.ParseDuration(probeTimeoutStr)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to parse the probe_timeout", fmt.Sprintf("The probe_timeout value '%s' can't be parsed: %s", probeTimeoutStr, err))
+		return
+	}
+
+	conn, serverAddr, err := dialSTUN("udp", server)
+	if err != nil {
+		log.Printf("STUN error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error contacting the STUN server", fmt.Sprintf("The STUN server '%s' could not be contacted: %s", server, err))
+		return
+	}
+	defer conn.Close()
+
+	natType, mapped, err := classifyNAT(ctx, conn, serverAddr, probeTimeout)
+	if err != nil {
+		log.Printf("NAT classification error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error classifying the NAT", fmt.Sprintf("The NAT could not be classified with the STUN server '%s': %s", server, err))
+		return
+	}
+
+	data.ID = types.String{Value: mapped.String()}
+	data.NATType = types.String{Value: natType}
+	data.MappedIP = types.String{Value: mapped.IP().Unmap().String()}
+	data.MappedPort = types.Int64{Value: int64(mapped.Port())}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// classifyNAT runs the tests of RFC 3489 section 10.1 against the STUN server.
+func classifyNAT(ctx context.Context, conn *net.UDPConn, server *net.UDPAddr, timeout time.Duration) (string, netaddr.IPPort, error) {
+	// Test I: Where does the request come from?
+	first, err := stunQuery(ctx, conn, server, false, false, timeout)
+	if err != nil {
+		return "", netaddr.IPPort{}, err
+	}
+
+	local, err := localSTUNAddr(conn, server)
+	if err != nil {
+		return "", first.mapped, err
+	}
+	if first.mapped == local {
+		return NATTypeNone, first.mapped, nil
+	}
+
+	// Test II: Does the NAT accept packets from anywhere?
+	_, err = stunQuery(ctx, conn, server, true, true, timeout)
+	if err == nil {
+		return NATTypeFullCone, first.mapped, nil
+	}
+	if err != errSTUNTimeout {
+		return "", first.mapped, err
+	}
+
+	if first.other.IsZero() {
+		return "", first.mapped, fmt.Errorf("the STUN server does not report an alternate address (RFC 5780)")
+	}
+
+	// Test I at the alternate address: Does the mapping depend on the destination?
+	second, err := stunQuery(ctx, conn, first.other.UDPAddr(), false, false, timeout)
+	if err != nil {
+		return "", first.mapped, err
+	}
+	if second.mapped != first.mapped {
+		return NATTypeSymmetric, first.mapped, nil
+	}
+
+	// Test III: Does the NAT accept packets from another port of the same IP?
+	_, err = stunQuery(ctx, conn, server, false, true, timeout)
+	if err == nil {
+		return NATTypeRestricted, first.mapped, nil
+	}
+	if err != errSTUNTimeout {
+		return "", first.mapped, err
+	}
+	return NATTypePortRestricted, first.mapped, nil
+}
+
+// localSTUNAddr returns the local address, which is used to reach the STUN server.
+// The socket itself is bound to the unspecified address, so the IP is determined by the route to the server.
+func localSTUNAddr(conn *net.UDPConn, server *net.UDPAddr) (netaddr.IPPort, error) {
+	route, err := net.DialUDP("udp", nil, server)
+	if err != nil {
+		return netaddr.IPPort{}, err
+	}
+	defer route.Close()
+
+	ip, _ := netaddr.FromStdIP(route.LocalAddr().(*net.UDPAddr).IP)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	return netaddr.IPPortFrom(ip, uint16(port)), nil
+}
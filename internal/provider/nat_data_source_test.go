@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+// testNATBehavior emulates a NAT in front of the client of the mock STUN server.
+type testNATBehavior struct {
+	// mapped returns the address of the client as seen by the socket of the server.
+	mapped func(socket int, from netaddr.IPPort) netaddr.IPPort
+	// accepts reports whether the NAT lets a response from another socket than the contacted one through.
+	accepts func(contacted int, respondFrom int) bool
+}
+
+// The sockets of the mock STUN server: the primary, the one with the other port and the one with the other IP and port.
+const (
+	testSTUNPrimary = iota
+	testSTUNChangedPort
+	testSTUNChangedIP
+)
+
+// testRFC5780Server starts a mock STUN server, which supports CHANGE-REQUEST and OTHER-ADDRESS.
+func testRFC5780Server(t *testing.T, behavior testNATBehavior) string {
+	t.Helper()
+
+	var sockets []*net.UDPConn
+	for _, ip := range []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 1), net.IPv4(127, 0, 0, 2)} {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ip})
+		if err != nil {
+			t.Skipf("unable to listen on '%s': %s", ip, err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		sockets = append(sockets, conn)
+	}
+	other, _ := netaddr.FromStdAddr(sockets[testSTUNChangedIP].LocalAddr().(*net.UDPAddr).IP, sockets[testSTUNChangedIP].LocalAddr().(*net.UDPAddr).Port, "")
+
+	for i, conn := range sockets {
+		go func(socket int, conn *net.UDPConn) {
+			buffer := make([]byte, 1500)
+			for {
+				n, fromAddr, err := conn.ReadFromUDP(buffer)
+				if err != nil {
+					return
+				}
+				request := buffer[:n]
+				from, _ := netaddr.FromStdAddr(fromAddr.IP, fromAddr.Port, "")
+
+				respondFrom := socket
+				if len(request) >= stunHeaderLength+8 && binary.BigEndian.Uint16(request[20:22]) == stunAttrChangeRequest {
+					flags := binary.BigEndian.Uint32(request[24:28])
+					if flags&stunChangeIP != 0 {
+						respondFrom = testSTUNChangedIP
+					} else if flags&stunChangePort != 0 {
+						respondFrom = testSTUNChangedPort
+					}
+				}
+				if respondFrom != socket && !behavior.accepts(socket, respondFrom) {
+					continue
+				}
+
+				response := testSTUNResponse(request, behavior.mapped(socket, from), true)
+				response = binary.BigEndian.AppendUint16(response, stunAttrOtherAddress)
+				response = binary.BigEndian.AppendUint16(response, 8)
+				response = append(response, 0, 0x01)
+				response = binary.BigEndian.AppendUint16(response, other.Port())
+				otherIP := other.IP().As4()
+				response = append(response, otherIP[:]...)
+				binary.BigEndian.PutUint16(response[2:4], uint16(len(response)-stunHeaderLength))
+
+				_, _ = sockets[respondFrom].WriteToUDP(response, fromAddr)
+			}
+		}(i, conn)
+	}
+
+	return sockets[testSTUNPrimary].LocalAddr().String()
+}
+
+func TestNATDataSource(t *testing.T) {
+	public := netaddr.MustParseIPPort("203.0.113.4:40000")
+	acceptAll := func(int, int) bool { return true }
+	acceptNone := func(int, int) bool { return false }
+
+	tests := map[string]struct {
+		behavior testNATBehavior
+		expected string
+	}{
+		NATTypeNone: {testNATBehavior{
+			mapped:  func(_ int, from netaddr.IPPort) netaddr.IPPort { return from },
+			accepts: acceptAll,
+		}, NATTypeNone},
+		NATTypeFullCone: {testNATBehavior{
+			mapped:  func(int, netaddr.IPPort) netaddr.IPPort { return public },
+			accepts: acceptAll,
+		}, NATTypeFullCone},
+		NATTypeRestricted: {testNATBehavior{
+			mapped: func(int, netaddr.IPPort) netaddr.IPPort { return public },
+			// Only responses from the contacted IP are let through.
+			accepts: func(_ int, respondFrom int) bool { return respondFrom != testSTUNChangedIP },
+		}, NATTypeRestricted},
+		NATTypePortRestricted: {testNATBehavior{
+			mapped:  func(int, netaddr.IPPort) netaddr.IPPort { return public },
+			accepts: acceptNone,
+		}, NATTypePortRestricted},
+		NATTypeSymmetric: {testNATBehavior{
+			mapped: func(socket int, _ netaddr.IPPort) netaddr.IPPort {
+				if socket == testSTUNChangedIP {
+					return netaddr.IPPortFrom(public.IP(), public.Port()+1)
+				}
+				return public
+			},
+			accepts: acceptNone,
+		}, NATTypeSymmetric},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := testRFC5780Server(t, test.behavior)
+
+			providerData := testProviderData(t, nil)
+			resp := testReadDataSource(t, NewNATDataSource, providerData, map[string]tftypes.Value{
+				"server":        tftypes.NewValue(tftypes.String, server),
+				"probe_timeout": tftypes.NewValue(tftypes.String, "100ms"),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data NATDataSourceModel
+			resp.State.Get(context.Background(), &data)
+			if data.NATType.Value != test.expected {
+				t.Errorf("expected nat_type '%s', got '%s'", test.expected, data.NATType.Value)
+			}
+		})
+	}
+}
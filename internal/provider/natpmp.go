@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"inet.af/netaddr"
+)
+
+// NAT-PMP (RFC 6886) and PCP (RFC 6887) both listen on this port of the gateway.
+const natpmpPort = 5351
+
+const (
+	natpmpVersion              = 0
+	natpmpOpExternalAddress    = 0
+	natpmpResultUnsupportedVer = 1
+
+	pcpVersion = 2
+	pcpOpMap   = 1
+	// pcpMapLifetime is how long the temporary mapping, which is needed to learn the external address with PCP, lives.
+	pcpMapLifetime = 120
+	pcpProtocolUDP = 17
+)
+
+// The names of the protocols in protocol_used.
+const (
+	ProtocolNATPMP = "nat-pmp"
+	ProtocolPCP    = "pcp"
+)
+
+var errNATPMPUnsupportedVersion = errors.New("the gateway does not support NAT-PMP")
+
+// natpmpResultCodes are the result codes of NAT-PMP, which share their meaning with PCP for the lower values.
+var natpmpResultCodes = map[byte]string{
+	1: "unsupported version",
+	2: "not authorized",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+func natpmpResultError(code byte) error {
+	if text, ok := natpmpResultCodes[code]; ok {
+		return fmt.Errorf("the gateway responded with the result code %d (%s)", code, text)
+	}
+	return fmt.Errorf("the gateway responded with the result code %d", code)
+}
+
+// natpmpExchange sends the request to the gateway and returns the first response, which matches.
+func natpmpExchange(ctx context.Context, conn *net.UDPConn, request []byte, matches func([]byte) bool, timeout time.Duration) ([]byte, error) {
+	_, err := conn.Write(request)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetReadDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, 1100)
+	for {
+		n, err := conn.Read(buffer)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("the gateway did not respond in time")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if matches(buffer[:n]) {
+			return buffer[:n], nil
+		}
+		log.Printf("ignoring NAT-PMP/PCP message 🗑️: %x", buffer[:n])
+	}
+}
+
+// natpmpExternalAddress asks the gateway for its external IPv4 with NAT-PMP.
+func natpmpExternalAddress(ctx context.Context, conn *net.UDPConn, timeout time.Duration) (netaddr.IP, error) {
+	log.Printf("sending NAT-PMP external address request 📨: %s", conn.RemoteAddr())
+
+	response, err := natpmpExchange(ctx, conn, []byte{natpmpVersion, natpmpOpExternalAddress}, func(msg []byte) bool {
+		// A PCP server responds to a NAT-PMP request with its own version and an error.
+		return len(msg) >= 4 && (msg[0] == pcpVersion || msg[1] == 128+natpmpOpExternalAddress)
+	}, timeout)
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	if response[0] != natpmpVersion {
+		return netaddr.IP{}, errNATPMPUnsupportedVersion
+	}
+	if result := binary.BigEndian.Uint16(response[2:4]); result != 0 {
+		if result == natpmpResultUnsupportedVer {
+			return netaddr.IP{}, errNATPMPUnsupportedVersion
+		}
+		return netaddr.IP{}, natpmpResultError(byte(result))
+	}
+	if len(response) < 12 {
+		return netaddr.IP{}, fmt.Errorf("the NAT-PMP response is too short")
+	}
+	return netaddr.IPFrom4([4]byte{response[8], response[9], response[10], response[11]}), nil
+}
+
+// pcpExternalAddress learns the external IP with a short-lived PCP MAP request for the UDP port of the connection.
+func pcpExternalAddress(ctx context.Context, conn *net.UDPConn, timeout time.Duration) (netaddr.IP, error) {
+	local, _ := netaddr.FromStdAddr(conn.LocalAddr().(*net.UDPAddr).IP, conn.LocalAddr().(*net.UDPAddr).Port, "")
+
+	var nonce [12]byte
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	request := make([]byte, 60)
+	request[0] = pcpVersion
+	request[1] = pcpOpMap
+	binary.BigEndian.PutUint32(request[4:8], pcpMapLifetime)
+	clientIP := local.IP().As16()
+	copy(request[8:24], clientIP[:])
+	copy(request[24:36], nonce[:])
+	request[36] = pcpProtocolUDP
+	binary.BigEndian.PutUint16(request[40:42], local.Port())
+
+	log.Printf("sending PCP MAP request 📨: %s", conn.RemoteAddr())
+
+	response, err := natpmpExchange(ctx, conn, request, func(msg []byte) bool {
+		return len(msg) >= 24 && msg[0] == pcpVersion && msg[1] == 128+pcpOpMap
+	}, timeout)
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+
+	if result := response[3]; result != 0 {
+		return netaddr.IP{}, natpmpResultError(result)
+	}
+	if len(response) < 60 || string(response[24:36]) != string(nonce[:]) {
+		return netaddr.IP{}, fmt.Errorf("the PCP response does not match the request")
+	}
+
+	var externalIP [16]byte
+	copy(externalIP[:], response[44:60])
+	return netaddr.IPFrom16(externalIP).Unmap(), nil
+}
+
+// defaultGateway returns the IPv4 gateway of the default route. It's only supported on Linux.
+func defaultGateway() (netaddr.IP, error) {
+	route, err := defaultRouteIPv4()
+	if err != nil {
+		return netaddr.IP{}, err
+	}
+	if route.gateway.IsZero() {
+		return netaddr.IP{}, fmt.Errorf("the default route over '%s' has no gateway", route.iface)
+	}
+	return route.gateway, nil
+}
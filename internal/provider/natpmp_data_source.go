@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type NATPMPDataSource struct {
+	provider *ProviderModel
+	// defaultGateway determines the gateway, if none is configured.
+	defaultGateway func() (netaddr.IP, error)
+	// port is the NAT-PMP/PCP port of the gateway.
+	port int
+}
+
+func NewNATPMPDataSource() datasource.DataSource {
+	return &NATPMPDataSource{defaultGateway: defaultGateway, port: natpmpPort}
+}
+
+func (d NATPMPDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_natpmp"
+}
+
+func (d NATPMPDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: fmt.Sprintf("The external IP as reported by the gateway using NAT-PMP (RFC 6886). If the gateway only supports PCP (RFC 6887), a temporary UDP mapping with a lifetime of %d seconds is requested to learn the external IP. No request leaves the local network.", pcpMapLifetime),
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"gateway": {
+				MarkdownDescription: "The IP of the gateway. Defaults to the gateway of the default route, which can only be determined on Linux.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"protocol_used": {
+				MarkdownDescription: fmt.Sprintf("The protocol, which the gateway answered. Expected values: '%s', '%s'", ProtocolNATPMP, ProtocolPCP),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The external IP of the gateway.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the external IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *NATPMPDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type NATPMPDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Gateway      types.String `tfsdk:"gateway"`
+	ProtocolUsed types.String `tfsdk:"protocol_used"`
+	IP           types.String `tfsdk:"ip"`
+	IPVersion    types.String `tfsdk:"ip_version"`
+}
+
+func (d NATPMPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data NATPMPDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var gateway netaddr.IP
+	var err error
+	if data.Gateway.Null || data.Gateway.Unknown {
+		gateway, err = d.defaultGateway()
+		if err != nil {
+			log.Printf("Gateway error 🚨: %s", err)
+			resp.Diagnostics.AddError("Unable to determine the gateway", fmt.Sprintf("The default gateway could not be determined: %s. Set the gateway explicitly.", err))
+			return
+		}
+	} else {
+		gateway, err = netaddr.ParseIP(data.Gateway.Value)
+		if err != nil {
+			log.Printf("Could not parse IP '%s' 🚨: %s", data.Gateway.Value, err)
+			resp.Diagnostics.AddError("Invalid gateway", fmt.Sprintf("The gateway '%s' could not be parsed: %s", data.Gateway.Value, err))
+			return
+		}
+	}
+
+	address := net.JoinHostPort(gateway.String(), strconv.Itoa(d.port))
+	ip, protocol, err := queryGatewayAddress(ctx, address, d.provider.timeout)
+	if err != nil {
+		log.Printf("NAT-PMP error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error querying the gateway", fmt.Sprintf("The external IP could not be queried from the gateway '%s': %s", address, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.Gateway = types.String{Value: gateway.String()}
+	data.ProtocolUsed = types.String{Value: protocol}
+	data.IP = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// queryGatewayAddress asks the gateway for its external IP with NAT-PMP and falls back to PCP.
+func queryGatewayAddress(ctx context.Context, address string, timeout time.Duration) (netaddr.IP, string, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return netaddr.IP{}, "", err
+	}
+	conn, err := net.DialUDP("udp", nil, udpAddr)
+	if err != nil {
+		return netaddr.IP{}, "", err
+	}
+	defer conn.Close()
+
+	ip, err := natpmpExternalAddress(ctx, conn, timeout)
+	if errors.Is(err, errNATPMPUnsupportedVersion) {
+		log.Printf("falling back to PCP 🔄: %s", err)
+		ip, err = pcpExternalAddress(ctx, conn, timeout)
+		return ip, ProtocolPCP, err
+	}
+	return ip, ProtocolNATPMP, err
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+// testNATPMPGateway starts a mock gateway, which responds to NAT-PMP or, if pcp is set, only to PCP with the externalIP.
+func testNATPMPGateway(t *testing.T, externalIP netaddr.IP, pcp bool) int {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1100)
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			request := buffer[:n]
+
+			var response []byte
+			switch {
+			case request[0] == natpmpVersion && pcp:
+				response = []byte{pcpVersion, 128 + request[1], 0, natpmpResultUnsupportedVer}
+				response = append(response, make([]byte, 20)...)
+			case request[0] == natpmpVersion:
+				response = []byte{natpmpVersion, 128 + natpmpOpExternalAddress, 0, 0, 0, 0, 0, 1}
+				ip := externalIP.As4()
+				response = append(response, ip[:]...)
+			case request[0] == pcpVersion && n == 60:
+				response = make([]byte, 60)
+				response[0] = pcpVersion
+				response[1] = 128 + pcpOpMap
+				binary.BigEndian.PutUint32(response[4:8], pcpMapLifetime)
+				copy(response[24:44], request[24:44])
+				ip := externalIP.As16()
+				copy(response[44:60], ip[:])
+			default:
+				continue
+			}
+			_, _ = conn.WriteToUDP(response, from)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func testNATPMPDataSource(port int) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &NATPMPDataSource{
+			defaultGateway: func() (netaddr.IP, error) { return netaddr.MustParseIP("127.0.0.1"), nil },
+			port:           port,
+		}
+	}
+}
+
+func TestNATPMPDataSource(t *testing.T) {
+	for _, protocol := range []string{ProtocolNATPMP, ProtocolPCP} {
+		t.Run(protocol, func(t *testing.T) {
+			port := testNATPMPGateway(t, netaddr.MustParseIP("203.0.113.4"), protocol == ProtocolPCP)
+
+			providerData := testProviderData(t, nil)
+			resp := testReadDataSource(t, testNATPMPDataSource(port), providerData, nil)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data NATPMPDataSourceModel
+			resp.State.Get(context.Background(), &data)
+			if data.IP.Value != "203.0.113.4" || data.IPVersion.Value != IPVersion4 || data.Gateway.Value != "127.0.0.1" || data.ProtocolUsed.Value != protocol {
+				t.Errorf("unexpected external address: %+v", data)
+			}
+		})
+	}
+}
+
+func TestNATPMPDataSourceInvalidGateway(t *testing.T) {
+	resp := testReadDataSource(t, testNATPMPDataSource(natpmpPort), nil, map[string]tftypes.Value{
+		"gateway": tftypes.NewValue(tftypes.String, "router"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid gateway")
+	}
+}
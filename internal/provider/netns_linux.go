@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// netnsSupported tells whether the connections can be made from another network namespace on this platform.
+const netnsSupported = true
+
+// netnsDialer opens connections from within a network namespace.
+// The namespace is entered by the OS thread of the dial only, the rest of the provider stays in its own namespace.
+type netnsDialer struct {
+	dialer contextDialer
+	// path of the namespace, e.g. `/var/run/netns/blue` or `/proc/1234/ns/net`.
+	path string
+}
+
+func (d netnsDialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 1)
+
+	go func() {
+		// The thread is only unlocked, if it is back in the original namespace.
+		// Otherwise it's terminated when the goroutine exits.
+		runtime.LockOSThread()
+
+		origin, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: fmt.Errorf("the current network namespace could not be opened: %w", err)}
+			return
+		}
+		defer origin.Close()
+
+		target, err := os.Open(d.path)
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: fmt.Errorf("the network namespace '%s' could not be opened: %w", d.path, err)}
+			return
+		}
+		defer target.Close()
+
+		err = unix.Setns(int(target.Fd()), unix.CLONE_NEWNET)
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: fmt.Errorf("the network namespace '%s' could not be entered: %w", d.path, err)}
+			return
+		}
+
+		conn, err := d.dialer.DialContext(ctx, network, addr)
+
+		if unix.Setns(int(origin.Fd()), unix.CLONE_NEWNET) == nil {
+			runtime.UnlockOSThread()
+		}
+		results <- result{conn: conn, err: err}
+	}()
+
+	r := <-results
+	return r.conn, r.err
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// netnsSupported tells whether the connections can be made from another network namespace on this platform.
+const netnsSupported = false
+
+// netnsDialer is only supported on Linux, where network namespaces exist.
+type netnsDialer struct {
+	dialer contextDialer
+	path   string
+}
+
+func (d netnsDialer) DialContext(_ context.Context, _ string, _ string) (net.Conn, error) {
+	return nil, fmt.Errorf("the network namespace '%s' can't be entered, because this is only supported on Linux", d.path)
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// The placeholders of the endpoint of a port checker.
+const (
+	PortCheckPlaceholderIP       = "{ip}"
+	PortCheckPlaceholderPort     = "{port}"
+	PortCheckPlaceholderProtocol = "{protocol}"
+)
+
+// portCheckResult is the response of a port checker. It's the format of the `/port/{port}` endpoint of echoip, e.g. ifconfig.co.
+type portCheckResult struct {
+	IP        string `json:"ip"`
+	Port      int64  `json:"port"`
+	Reachable bool   `json:"reachable"`
+
+	latency time.Duration
+}
+
+// checkPort asks the port checker at the checkURL whether the port is reachable from the internet.
+func checkPort(ctx context.Context, p *ProviderModel, client *http.Client, checkURL string) (portCheckResult, error) {
+	log.Printf("checking the port 🔍: %s", checkURL)
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", checkURL, nil)
+	if err != nil {
+		return portCheckResult{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", p.nextUserAgent())
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return portCheckResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return portCheckResult{}, fmt.Errorf("the port checker responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	var result portCheckResult
+	err = json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes)).Decode(&result)
+	if err != nil {
+		return portCheckResult{}, err
+	}
+	result.latency = time.Since(start)
+
+	log.Printf("got port check ✅: %+v", result)
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const (
+	PortCheckProtocolTCP = "tcp"
+	PortCheckProtocolUDP = "udp"
+)
+
+type PortCheckDataSource struct {
+	provider *ProviderModel
+}
+
+func NewPortCheckDataSource() datasource.DataSource {
+	return &PortCheckDataSource{}
+}
+
+func (d PortCheckDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_port_check"
+}
+
+func (d PortCheckDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Asks an external port checker whether a port on the current public IP is reachable from the internet, e.g. to validate a port forwarding.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"port": {
+				MarkdownDescription: "The port to check.",
+				Required:            true,
+				Type:                types.Int64Type,
+			},
+			"protocol": {
+				MarkdownDescription: fmt.Sprintf("The protocol of the port. Expected values: '%s', '%s'. Defaults to `%s`.", PortCheckProtocolTCP, PortCheckProtocolUDP, PortCheckProtocolTCP),
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"endpoint": {
+				MarkdownDescription: fmt.Sprintf("The URL of the port checker. The placeholders `%s`, `%s` and `%s` are replaced by the port, the protocol and the current public IP. The port checker must respond like echoip, e.g. `{\"ip\":\"203.0.113.4\",\"port\":443,\"reachable\":true}`. Defaults to the `/port/%s` endpoint of the IP information provider, which only checks TCP.", PortCheckPlaceholderPort, PortCheckPlaceholderProtocol, PortCheckPlaceholderIP, PortCheckPlaceholderPort),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The public IP, which was checked. `null` if neither the port checker responded with it nor the `endpoint` contains the placeholder of the IP.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"reachable": {
+				MarkdownDescription: "`true` if the port checker could reach the port.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"latency_ms": {
+				MarkdownDescription: "The time in milliseconds it took the port checker to respond, which includes its probe of the port.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+		},
+	}, nil
+}
+
+func (d *PortCheckDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type PortCheckDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Port      types.Int64  `tfsdk:"port"`
+	Protocol  types.String `tfsdk:"protocol"`
+	Endpoint  types.String `tfsdk:"endpoint"`
+	IP        types.String `tfsdk:"ip"`
+	Reachable types.Bool   `tfsdk:"reachable"`
+	LatencyMS types.Int64  `tfsdk:"latency_ms"`
+}
+
+func (d PortCheckDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data PortCheckDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Port.Value < 1 || data.Port.Value > 65535 {
+		resp.Diagnostics.AddError("Invalid port", fmt.Sprintf("The port '%d' must be between 1 and 65535.", data.Port.Value))
+		return
+	}
+
+	protocol := PortCheckProtocolTCP
+	if !data.Protocol.Null && !data.Protocol.Unknown {
+		protocol = data.Protocol.Value
+	}
+	if protocol != PortCheckProtocolTCP && protocol != PortCheckProtocolUDP {
+		resp.Diagnostics.AddError("Invalid protocol", fmt.Sprintf("The protocol '%s' must be either '%s' or '%s'.", protocol, PortCheckProtocolTCP, PortCheckProtocolUDP))
+		return
+	}
+
+	endpoint := data.Endpoint.Value
+	if data.Endpoint.Null {
+		if protocol != PortCheckProtocolTCP {
+			resp.Diagnostics.AddError("Missing endpoint", fmt.Sprintf("The IP information provider only checks '%s' ports, set the endpoint of a port checker for '%s'.", PortCheckProtocolTCP, protocol))
+			return
+		}
+		endpoint = strings.TrimSuffix(d.provider.ipProviderURLs[0].String(), "/") + "/port/" + PortCheckPlaceholderPort
+	}
+
+	// The data source has no ip attribute, so the public IP is only fetched, if the endpoint of the port checker asks for it.
+	var ip netaddr.IP
+	if strings.Contains(endpoint, PortCheckPlaceholderIP) {
+		ip, _, diags = resolveTargetIP(ctx, d.provider, types.String{Null: true})
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	checkURL := strings.NewReplacer(
+		PortCheckPlaceholderPort, strconv.FormatInt(data.Port.Value, 10),
+		PortCheckPlaceholderProtocol, protocol,
+		PortCheckPlaceholderIP, ip.String(),
+	).Replace(endpoint)
+
+	result, err := checkPort(ctx, d.provider, newLookupHTTPClient(d.provider), checkURL)
+	if err != nil {
+		log.Printf("Port check error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error checking the port", fmt.Sprintf("The port %d/%s could not be checked with '%s': %s", data.Port.Value, protocol, checkURL, err))
+		return
+	}
+
+	if result.IP != "" {
+		ip, err = netaddr.ParseIP(result.IP)
+		if err != nil {
+			log.Printf("IP '%s' decode error 🚨: %s", result.IP, err)
+			resp.Diagnostics.AddError("Error parsing the IP from the port checker", fmt.Sprintf("The IP '%s' of the response from the port checker can't be parsed: %s", result.IP, err))
+			return
+		}
+	}
+
+	data.ID = types.String{Value: fmt.Sprintf("%s$%d/%s", ip, data.Port.Value, protocol)}
+	data.Protocol = types.String{Value: protocol}
+	data.IP = types.String{Null: true}
+	if !ip.IsZero() {
+		data.IP = types.String{Value: ip.String()}
+	}
+	data.Reachable = types.Bool{Value: result.Reachable}
+	data.LatencyMS = types.Int64{Value: result.latency.Milliseconds()}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPortCheckDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+		case "/port/443":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4","port":443,"reachable":true}`))
+		case "/port/8080":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4","port":8080,"reachable":false}`))
+		case "/check/203.0.113.4/udp/51820":
+			_, _ = w.Write([]byte(`{"port":51820,"reachable":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	var data PortCheckDataSourceModel
+	for port, reachable := range map[int64]bool{443: true, 8080: false} {
+		resp := testReadDataSource(t, NewPortCheckDataSource, providerData, map[string]tftypes.Value{
+			"port": tftypes.NewValue(tftypes.Number, port),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		resp.State.Get(context.Background(), &data)
+		if data.IP.Value != "203.0.113.4" || data.Protocol.Value != PortCheckProtocolTCP || data.Reachable.Value != reachable || data.LatencyMS.Null {
+			t.Errorf("unexpected check of port %d: %+v", port, data)
+		}
+	}
+
+	resp := testReadDataSource(t, NewPortCheckDataSource, providerData, map[string]tftypes.Value{
+		"port":     tftypes.NewValue(tftypes.Number, 51820),
+		"protocol": tftypes.NewValue(tftypes.String, PortCheckProtocolUDP),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/check/{ip}/{protocol}/{port}"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || !data.Reachable.Value || data.ID.Value != "203.0.113.4$51820/udp" {
+		t.Errorf("unexpected check with a custom endpoint: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewPortCheckDataSource, providerData, map[string]tftypes.Value{
+		"port":     tftypes.NewValue(tftypes.Number, 51820),
+		"protocol": tftypes.NewValue(tftypes.String, PortCheckProtocolUDP),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected an error for UDP without an endpoint")
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type PrefixDataSource struct {
+	provider *ProviderModel
+}
+
+func NewPrefixDataSource() datasource.DataSource {
+	return &PrefixDataSource{}
+}
+
+func (d PrefixDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_prefix"
+}
+
+func (d PrefixDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Looks up the announced BGP prefix, which covers the current public IP or an arbitrary IP, using RIPEstat. Useful to allowlist the whole prefix of the ISP instead of a single IP. See `ripestat_url` on the provider.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"announced": {
+				MarkdownDescription: "`true` if a prefix covering the IP is announced in BGP.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"prefix": {
+				MarkdownDescription: "The most specific announced prefix, which covers the IP, e.g. `193.0.0.0/21`. `null` if it is not `announced`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"origin_asn": {
+				MarkdownDescription: "The ASN, which originates the prefix, e.g. `AS3333`. `null` if it is not `announced`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"holder": {
+				MarkdownDescription: "The holder of the origin ASN. `null` if it is not `announced`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *PrefixDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type PrefixDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	IP        types.String `tfsdk:"ip"`
+	Announced types.Bool   `tfsdk:"announced"`
+	Prefix    types.String `tfsdk:"prefix"`
+	OriginASN types.String `tfsdk:"origin_asn"`
+	Holder    types.String `tfsdk:"holder"`
+}
+
+func (d PrefixDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data PrefixDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, _, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix, err := lookupRIPEstatPrefix(ctx, d.provider, newLookupHTTPClient(d.provider), ip)
+	if err != nil {
+		log.Printf("RIPEstat error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error looking up the prefix", fmt.Sprintf("The announced prefix of '%s' could not be looked up: %s", ip, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.Announced = types.Bool{Value: prefix.announced}
+	if prefix.announced {
+		data.Prefix = types.String{Value: prefix.prefix}
+		data.OriginASN = types.String{Value: fmt.Sprintf("AS%d", prefix.asn)}
+		data.Holder = types.String{Value: prefix.holder}
+	} else {
+		data.Prefix = types.String{Null: true}
+		data.OriginASN = types.String{Null: true}
+		data.Holder = types.String{Null: true}
+	}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testRIPEstatServer(t *testing.T) string {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/json":
+			_, _ = w.Write([]byte(`{"ip":"193.0.6.139"}`))
+		case r.URL.Path != "/ripestat/data/prefix-overview/data.json" || r.URL.Query().Get("sourceapp") != ripestatSourceApp:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Query().Get("resource") == "193.0.6.139":
+			_, _ = w.Write([]byte(`{"status":"ok","data":{"announced":true,"resource":"193.0.0.0/21","asns":[{"asn":3333,"holder":"RIPE-NCC-AS - Reseaux IP Europeens Network Coordination Centre (RIPE NCC)"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"ok","data":{"announced":false,"resource":"` + r.URL.Query().Get("resource") + `","asns":[]}}`))
+		}
+	})
+	return server.URL
+}
+
+func TestPrefixDataSourceCurrentIP(t *testing.T) {
+	serverURL := testRIPEstatServer(t)
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, serverURL),
+		"ripestat_url": tftypes.NewValue(tftypes.String, serverURL+"/ripestat/"),
+	})
+	resp := testReadDataSource(t, NewPrefixDataSource, providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data PrefixDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "193.0.6.139" || !data.Announced.Value || data.Prefix.Value != "193.0.0.0/21" || data.OriginASN.Value != "AS3333" || data.Holder.Null {
+		t.Errorf("unexpected prefix: %+v", data)
+	}
+}
+
+func TestPrefixDataSourceNotAnnounced(t *testing.T) {
+	serverURL := testRIPEstatServer(t)
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"ripestat_url": tftypes.NewValue(tftypes.String, serverURL+"/ripestat/"),
+	})
+	resp := testReadDataSource(t, NewPrefixDataSource, providerData, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "192.0.2.1"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data PrefixDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.Announced.Value || !data.Prefix.Null || !data.OriginASN.Null || !data.Holder.Null {
+		t.Errorf("expected no announced prefix: %+v", data)
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	TypeName: providerserver.NewProtocol6WithError(New("test")()),
+}
+
+func testAccPreCheck(t *testing.T) {
+	// You can add code here to run prior to any test case execution, for example assertions
+	// about the appropriate environment variables being set are common to see in a pre-check
+	// function.
+}
+
+// testIPServer starts a mock IP information provider.
+func testIPServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testConfigValue builds the raw configuration for the given schema.
+// All attributes that are not given are null.
+func testConfigValue(ctx context.Context, schema tfsdk.Schema, attributes map[string]tftypes.Value) tftypes.Value {
+	objectType := schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+		} else {
+			values[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+
+	return tftypes.NewValue(objectType, values)
+}
+
+// testProviderData configures the provider without invoking Terraform and returns the data passed to the data sources.
+func testProviderData(t *testing.T, attributes map[string]tftypes.Value) *ProviderModel {
+	t.Helper()
+	ctx := context.Background()
+
+	p := New("test")()
+	schema, diags := p.GetSchema(ctx)
+	if diags.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", diags)
+	}
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schema, Raw: testConfigValue(ctx, schema, attributes)},
+	}
+	resp := provider.ConfigureResponse{}
+	p.Configure(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected configure diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp.DataSourceData.(*ProviderModel)
+}
+
+// testReadDataSource reads the data source without invoking Terraform.
+func testReadDataSource(t *testing.T, newDataSource func() datasource.DataSource, providerData *ProviderModel, attributes map[string]tftypes.Value) datasource.ReadResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	d := newDataSource()
+	if configurable, ok := d.(datasource.DataSourceWithConfigure); ok {
+		configureResp := datasource.ConfigureResponse{}
+		configurable.Configure(ctx, datasource.ConfigureRequest{ProviderData: providerData}, &configureResp)
+		if configureResp.Diagnostics.HasError() {
+			t.Fatalf("unexpected configure diagnostics: %v", configureResp.Diagnostics)
+		}
+	}
+
+	schema, diags := d.GetSchema(ctx)
+	if diags.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", diags)
+	}
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Schema: schema, Raw: testConfigValue(ctx, schema, attributes)},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)},
+	}
+	d.Read(ctx, req, &resp)
+
+	return resp
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RDNSDataSource struct {
+	provider *ProviderModel
+	// lookupAddr resolves the PTR records of an IP.
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+}
+
+func NewRDNSDataSource() datasource.DataSource {
+	return &RDNSDataSource{lookupAddr: net.DefaultResolver.LookupAddr}
+}
+
+func (d RDNSDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rdns"
+}
+
+func (d RDNSDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Looks up the reverse DNS (PTR) names of the current public IP or of an arbitrary IP using the local resolver.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"names": {
+				MarkdownDescription: "The PTR names of the IP without the trailing dot, e.g. `mail.example.com`. Empty if the IP has none.",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+		},
+	}, nil
+}
+
+func (d *RDNSDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type RDNSDataSourceModel struct {
+	ID    types.String `tfsdk:"id"`
+	IP    types.String `tfsdk:"ip"`
+	Names []string     `tfsdk:"names"`
+}
+
+func (d RDNSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data RDNSDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, _, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	log.Printf("looking up the PTR names 🔍: %s", ip)
+
+	names, err := d.lookupAddr(ctx, ip.String())
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		names, err = nil, nil
+	}
+	if err != nil {
+		log.Printf("Reverse DNS error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error looking up the reverse DNS", fmt.Sprintf("The PTR names of '%s' could not be looked up: %s", ip, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.Names = []string{}
+	for _, name := range names {
+		data.Names = append(data.Names, strings.TrimSuffix(name, "."))
+	}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
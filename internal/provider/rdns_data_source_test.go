@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func testRDNSDataSource(names map[string][]string) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &RDNSDataSource{lookupAddr: func(_ context.Context, addr string) ([]string, error) {
+			if names, ok := names[addr]; ok {
+				return names, nil
+			}
+			return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+		}}
+	}
+}
+
+func TestRDNSDataSourceCurrentIP(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	resp := testReadDataSource(t, testRDNSDataSource(map[string][]string{
+		"203.0.113.4": {"mail.example.com.", "example.com."},
+	}), providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RDNSDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || len(data.Names) != 2 || data.Names[0] != "mail.example.com" || data.Names[1] != "example.com" {
+		t.Errorf("unexpected reverse DNS: %+v", data)
+	}
+}
+
+func TestRDNSDataSourceWithoutPTR(t *testing.T) {
+	resp := testReadDataSource(t, testRDNSDataSource(nil), nil, map[string]tftypes.Value{
+		"ip": tftypes.NewValue(tftypes.String, "2001:db8::1"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RDNSDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "2001:db8::1" || data.Names == nil || len(data.Names) != 0 {
+		t.Errorf("expected no names: %+v", data)
+	}
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RemoteAddressDataSource struct {
+	provider *ProviderModel
+	// sshCommand creates the command of the OpenSSH client with the arguments.
+	sshCommand func(ctx context.Context, args ...string) *exec.Cmd
+}
+
+func NewRemoteAddressDataSource() datasource.DataSource {
+	return &RemoteAddressDataSource{sshCommand: func(ctx context.Context, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "ssh", args...)
+	}}
+}
+
+func (d RemoteAddressDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_remote_address"
+}
+
+func (d RemoteAddressDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The public IP of a remote host, e.g. a bastion. The request to the IP information provider is forwarded through an SSH connection to the remote host (`ssh -W`), so the IP information provider sees the remote host. The OpenSSH client `ssh` must be installed; its configuration, agent and known hosts apply. The connection is non-interactive, so password authentication is not supported.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The public IP of the remote host as returned by the IP information provider.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"connection": {
+				MarkdownDescription: "The SSH connection to the remote host. Required.",
+				NestingMode:         tfsdk.BlockNestingModeSingle,
+				Attributes: map[string]tfsdk.Attribute{
+					"host": {
+						MarkdownDescription: "The remote host, either as name, IP or alias of the SSH configuration.",
+						Required:            true,
+						Type:                types.StringType,
+					},
+					"port": {
+						MarkdownDescription: "The SSH port of the remote host. Defaults to the SSH configuration, usually `22`.",
+						Optional:            true,
+						Type:                types.Int64Type,
+					},
+					"user": {
+						MarkdownDescription: "The user on the remote host. Defaults to the SSH configuration.",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"identity_file": {
+						MarkdownDescription: "The path to the private key. Defaults to the SSH configuration and the SSH agent.",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"options": {
+						MarkdownDescription: "Additional options of the OpenSSH client, e.g. `StrictHostKeyChecking=accept-new` or `ProxyJump=jump.example.com`.",
+						Optional:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (d *RemoteAddressDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type RemoteAddressDataSourceModel struct {
+	ID         types.String        `tfsdk:"id"`
+	IP         types.String        `tfsdk:"ip"`
+	IPVersion  types.String        `tfsdk:"ip_version"`
+	Connection *SSHConnectionModel `tfsdk:"connection"`
+}
+
+type SSHConnectionModel struct {
+	Host         types.String `tfsdk:"host"`
+	Port         types.Int64  `tfsdk:"port"`
+	User         types.String `tfsdk:"user"`
+	IdentityFile types.String `tfsdk:"identity_file"`
+	Options      []string     `tfsdk:"options"`
+}
+
+func (d RemoteAddressDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data RemoteAddressDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Connection == nil {
+		resp.Diagnostics.AddError("Missing connection", "The connection block is required.")
+		return
+	}
+
+	dialer := sshDialer{
+		host:         data.Connection.Host.Value,
+		port:         data.Connection.Port.Value,
+		user:         data.Connection.User.Value,
+		identityFile: data.Connection.IdentityFile.Value,
+		options:      data.Connection.Options,
+		command:      d.sshCommand,
+	}
+	client := &http.Client{Transport: &http.Transport{DialContext: dialer.DialContext, DisableKeepAlives: true}}
+
+	result, diags := fetchIPFromProviders(ctx, d.provider, client, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.String{Value: data.Connection.Host.Value + "$" + result.ip.String()}
+	data.IP = types.String{Value: result.ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(result.ip)}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// TestSSHHelperProcess isn't a real test. It emulates `ssh -W host:port` for the tests of publicip_remote_address.
+func TestSSHHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_SSH_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	var destination, host string
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	args = args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-W":
+			destination = args[i+1]
+			i++
+		case "--":
+			host = args[i+1]
+			i++
+		}
+	}
+	if host != "bastion.test" {
+		_, _ = os.Stderr.WriteString("ssh: Could not resolve hostname " + host + ": Name or service not known\n")
+		os.Exit(255)
+	}
+
+	conn, err := net.Dial("tcp", destination)
+	if err != nil {
+		_, _ = os.Stderr.WriteString("channel 0: open failed: " + err.Error() + "\n")
+		os.Exit(255)
+	}
+	go func() { _, _ = io.Copy(conn, os.Stdin) }()
+	_, _ = io.Copy(os.Stdout, conn)
+}
+
+func testRemoteAddressDataSource() datasource.DataSource {
+	return &RemoteAddressDataSource{sshCommand: func(ctx context.Context, args ...string) *exec.Cmd {
+		cmd := exec.CommandContext(ctx, os.Args[0], append([]string{"-test.run=TestSSHHelperProcess", "--"}, args...)...)
+		cmd.Env = append(os.Environ(), "GO_WANT_SSH_HELPER_PROCESS=1")
+		return cmd
+	}}
+}
+
+func TestRemoteAddressDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	connectionType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"host":          tftypes.String,
+		"port":          tftypes.Number,
+		"user":          tftypes.String,
+		"identity_file": tftypes.String,
+		"options":       tftypes.List{ElementType: tftypes.String},
+	}}
+	connection := func(host string) tftypes.Value {
+		return tftypes.NewValue(connectionType, map[string]tftypes.Value{
+			"host":          tftypes.NewValue(tftypes.String, host),
+			"port":          tftypes.NewValue(tftypes.Number, 2222),
+			"user":          tftypes.NewValue(tftypes.String, "terraform"),
+			"identity_file": tftypes.NewValue(tftypes.String, nil),
+			"options": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, "StrictHostKeyChecking=accept-new"),
+			}),
+		})
+	}
+
+	resp := testReadDataSource(t, testRemoteAddressDataSource, providerData, map[string]tftypes.Value{
+		"connection": connection("bastion.test"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RemoteAddressDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.IPVersion.Value != IPVersion4 {
+		t.Errorf("unexpected remote address: %+v", data)
+	}
+
+	resp = testReadDataSource(t, testRemoteAddressDataSource, providerData, map[string]tftypes.Value{
+		"connection": connection("unknown.test"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an unknown host")
+	}
+}
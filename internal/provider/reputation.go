@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"inet.af/netaddr"
+)
+
+// The supported reputation services.
+const (
+	ReputationServiceAbuseIPDB = "abuseipdb"
+	ReputationServiceIPInfo    = "ipinfo"
+)
+
+// DefaultReputationEndpoints are the API endpoints of the reputation services.
+var DefaultReputationEndpoints = map[string]string{
+	ReputationServiceAbuseIPDB: "https://api.abuseipdb.com/api/v2/",
+	ReputationServiceIPInfo:    "https://ipinfo.io/",
+}
+
+// reputation is the normalized result of a reputation service. Flags, which the service does not provide, are nil.
+type reputation struct {
+	abuseScore *int64
+	proxy      *bool
+	vpn        *bool
+	hosting    *bool
+	tor        *bool
+}
+
+// lookupReputation queries the reputation service at the endpoint for the IP.
+func lookupReputation(ctx context.Context, p *ProviderModel, client *http.Client, service string, endpoint *url.URL, apiKey string, ip netaddr.IP) (reputation, error) {
+	lookupURL := *endpoint
+	query := lookupURL.Query()
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", p.nextUserAgent())
+
+	switch service {
+	case ReputationServiceAbuseIPDB:
+		lookupURL.Path = path.Join(lookupURL.Path, "check")
+		query.Set("ipAddress", ip.String())
+		header.Set("Key", apiKey)
+	case ReputationServiceIPInfo:
+		lookupURL.Path = path.Join(lookupURL.Path, ip.String(), "privacy")
+		header.Set("Authorization", "Bearer "+apiKey)
+	default:
+		return reputation{}, fmt.Errorf("the reputation service '%s' is not supported", service)
+	}
+	lookupURL.RawQuery = query.Encode()
+
+	// The API key is sent in a header, so the URL can be logged.
+	log.Printf("looking up the reputation 🔍: %s", lookupURL.String())
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", lookupURL.String(), nil)
+	if err != nil {
+		return reputation{}, err
+	}
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return reputation{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return reputation{}, fmt.Errorf("the reputation service responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	var result reputation
+	switch service {
+	case ReputationServiceAbuseIPDB:
+		var check struct {
+			Data struct {
+				AbuseConfidenceScore int64  `json:"abuseConfidenceScore"`
+				UsageType            string `json:"usageType"`
+				IsTor                bool   `json:"isTor"`
+			} `json:"data"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes)).Decode(&check)
+		if err != nil {
+			return reputation{}, err
+		}
+		// The usage type is e.g. `Data Center/Web Hosting/Transit`.
+		hosting := strings.Contains(check.Data.UsageType, "Hosting") || strings.Contains(check.Data.UsageType, "Data Center")
+		result = reputation{abuseScore: &check.Data.AbuseConfidenceScore, hosting: &hosting, tor: &check.Data.IsTor}
+	case ReputationServiceIPInfo:
+		var privacy struct {
+			VPN     bool `json:"vpn"`
+			Proxy   bool `json:"proxy"`
+			Tor     bool `json:"tor"`
+			Hosting bool `json:"hosting"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes)).Decode(&privacy)
+		if err != nil {
+			return reputation{}, err
+		}
+		result = reputation{proxy: &privacy.Proxy, vpn: &privacy.VPN, hosting: &privacy.Hosting, tor: &privacy.Tor}
+	}
+
+	log.Printf("got reputation ✅: %s", ip)
+	return result, nil
+}
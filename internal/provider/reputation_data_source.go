@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ReputationDataSource struct {
+	provider *ProviderModel
+}
+
+func NewReputationDataSource() datasource.DataSource {
+	return &ReputationDataSource{}
+}
+
+func (d ReputationDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_reputation"
+}
+
+func (d ReputationDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: fmt.Sprintf("Looks up the reputation of the current public IP or of an arbitrary IP with a reputation service. `%s` provides the `abuse_score`, `is_hosting` and `is_tor`, `%s` (privacy detection) provides all flags but no `abuse_score`. Values, which the service does not provide, are `null`.", ReputationServiceAbuseIPDB, ReputationServiceIPInfo),
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up. Defaults to the current public IP as returned by the IP information provider.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"service": {
+				MarkdownDescription: fmt.Sprintf("The reputation service. Expected values: '%s', '%s'", ReputationServiceAbuseIPDB, ReputationServiceIPInfo),
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"endpoint": {
+				MarkdownDescription: fmt.Sprintf("The URL of the API of the reputation service. Defaults to `%s` for '%s' and `%s` for '%s'.", DefaultReputationEndpoints[ReputationServiceAbuseIPDB], ReputationServiceAbuseIPDB, DefaultReputationEndpoints[ReputationServiceIPInfo], ReputationServiceIPInfo),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"api_key": {
+				MarkdownDescription: "The API key (AbuseIPDB) or token (ipinfo) of the reputation service.",
+				Required:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"abuse_score": {
+				MarkdownDescription: "The confidence, from 0 to 100, that the IP is abusive.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+			"is_proxy": {
+				MarkdownDescription: "`true` if the IP is an open proxy.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_vpn": {
+				MarkdownDescription: "`true` if the IP is the exit of a VPN.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_hosting": {
+				MarkdownDescription: "`true` if the IP belongs to a hosting provider or data center.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"is_tor": {
+				MarkdownDescription: "`true` if the IP is a Tor exit node.",
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+		},
+	}, nil
+}
+
+func (d *ReputationDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type ReputationDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	IP         types.String `tfsdk:"ip"`
+	Service    types.String `tfsdk:"service"`
+	Endpoint   types.String `tfsdk:"endpoint"`
+	APIKey     types.String `tfsdk:"api_key"`
+	AbuseScore types.Int64  `tfsdk:"abuse_score"`
+	IsProxy    types.Bool   `tfsdk:"is_proxy"`
+	IsVPN      types.Bool   `tfsdk:"is_vpn"`
+	IsHosting  types.Bool   `tfsdk:"is_hosting"`
+	IsTor      types.Bool   `tfsdk:"is_tor"`
+}
+
+func (d ReputationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data ReputationDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	endpointStr, ok := DefaultReputationEndpoints[data.Service.Value]
+	if !ok {
+		resp.Diagnostics.AddError("Invalid service", fmt.Sprintf("The service '%s' must be either '%s' or '%s'.", data.Service.Value, ReputationServiceAbuseIPDB, ReputationServiceIPInfo))
+		return
+	}
+	if !data.Endpoint.Null {
+		endpointStr = data.Endpoint.Value
+	}
+	endpoint, err := url.Parse(endpointStr)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to parse the endpoint", fmt.Sprintf("The endpoint value '%s' can't be parsed: %s", endpointStr, err))
+		return
+	}
+
+	ip, _, diags := resolveTargetIP(ctx, d.provider, data.IP)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rep, err := lookupReputation(ctx, d.provider, newLookupHTTPClient(d.provider), data.Service.Value, endpoint, data.APIKey.Value, ip)
+	if err != nil {
+		log.Printf("Reputation error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error looking up the reputation", fmt.Sprintf("The reputation of '%s' could not be looked up with '%s': %s", ip, data.Service.Value, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.AbuseScore = types.Int64{Null: true}
+	if rep.abuseScore != nil {
+		data.AbuseScore = types.Int64{Value: *rep.abuseScore}
+	}
+	data.IsProxy = optionalBool(rep.proxy)
+	data.IsVPN = optionalBool(rep.vpn)
+	data.IsHosting = optionalBool(rep.hosting)
+	data.IsTor = optionalBool(rep.tor)
+
+	logged := data
+	logged.APIKey = types.String{Value: "(sensitive)"}
+	log.Printf("got to state update ✅: %+v", logged)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func optionalBool(value *bool) types.Bool {
+	if value == nil {
+		return types.Bool{Null: true}
+	}
+	return types.Bool{Value: *value}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestReputationDataSource(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/json":
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+		case r.URL.Path == "/abuseipdb/check" && r.Header.Get("Key") == "secret" && r.URL.Query().Get("ipAddress") == "203.0.113.4":
+			_, _ = w.Write([]byte(`{"data":{"ipAddress":"203.0.113.4","abuseConfidenceScore":42,"usageType":"Data Center/Web Hosting/Transit","isTor":false}}`))
+		case r.URL.Path == "/ipinfo/203.0.113.4/privacy" && r.Header.Get("Authorization") == "Bearer secret":
+			_, _ = w.Write([]byte(`{"vpn":true,"proxy":false,"tor":false,"relay":false,"hosting":false,"service":""}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 10),
+	})
+
+	resp := testReadDataSource(t, NewReputationDataSource, providerData, map[string]tftypes.Value{
+		"service":  tftypes.NewValue(tftypes.String, ReputationServiceAbuseIPDB),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/abuseipdb/"),
+		"api_key":  tftypes.NewValue(tftypes.String, "secret"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data ReputationDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.AbuseScore.Value != 42 || !data.IsHosting.Value || data.IsTor.Value || !data.IsProxy.Null || !data.IsVPN.Null {
+		t.Errorf("unexpected abuseipdb reputation: %+v", data)
+	}
+
+	resp = testReadDataSource(t, NewReputationDataSource, providerData, map[string]tftypes.Value{
+		"ip":       tftypes.NewValue(tftypes.String, "203.0.113.4"),
+		"service":  tftypes.NewValue(tftypes.String, ReputationServiceIPInfo),
+		"endpoint": tftypes.NewValue(tftypes.String, server.URL+"/ipinfo/"),
+		"api_key":  tftypes.NewValue(tftypes.String, "secret"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	resp.State.Get(context.Background(), &data)
+	if !data.AbuseScore.Null || !data.IsVPN.Value || data.IsProxy.Value || data.IsHosting.Value {
+		t.Errorf("unexpected ipinfo reputation: %+v", data)
+	}
+}
+
+func TestReputationDataSourceInvalidService(t *testing.T) {
+	resp := testReadDataSource(t, NewReputationDataSource, nil, map[string]tftypes.Value{
+		"service": tftypes.NewValue(tftypes.String, "spamhaus"),
+		"api_key": tftypes.NewValue(tftypes.String, "secret"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for an invalid service")
+	}
+}
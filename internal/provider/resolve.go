@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"inet.af/netaddr"
+)
+
+// resolveIP parses an IP or resolves a hostname locally.
+// If the hostname resolves to addresses of both families, an address of the preferredVersion (IPVersion4 or IPVersion6) is chosen.
+func resolveIP(ctx context.Context, host string, preferredVersion string) (netaddr.IP, error) {
+	ip, err := netaddr.ParseIP(host)
+	if err == nil && ip.IsValid() {
+		return ip, nil
+	}
+
+	log.Printf("'%s' is not an IP, resolving it 🔍", host)
+
+	addrs, resolveErr := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if resolveErr != nil {
+		return netaddr.IP{}, fmt.Errorf("'%s' is neither a valid IP (%s) nor a resolvable hostname (%s)", host, err, resolveErr)
+	}
+
+	var candidates []netaddr.IP
+	for _, addr := range addrs {
+		ip, ok := ipFromAddr(addr)
+		if ok {
+			candidates = append(candidates, ip)
+		}
+	}
+	if len(candidates) == 0 {
+		return netaddr.IP{}, fmt.Errorf("the hostname '%s' did not resolve to any IP", host)
+	}
+
+	for _, candidate := range candidates {
+		if ipVersion(candidate) == preferredVersion {
+			log.Printf("resolved '%s' to '%s' ✅", host, candidate)
+			return candidate, nil
+		}
+	}
+
+	log.Printf("resolved '%s' to '%s' ✅", host, candidates[0])
+	return candidates[0], nil
+}
+
+// ipFromAddr converts a resolved address, keeping the zone of e.g. a link-local IPv6.
+// IPv4 addresses in their 16 byte form are unmapped, as they have no zone.
+func ipFromAddr(addr net.IPAddr) (netaddr.IP, bool) {
+	ip, ok := netaddr.FromStdIPRaw(addr.IP)
+	if !ok {
+		return netaddr.IP{}, false
+	}
+	return ip.Unmap().WithZone(addr.Zone), true
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestResolveIP(t *testing.T) {
+	tests := map[string]struct {
+		sourceIP         string
+		preferredVersion string
+		expected         string
+	}{
+		"v4":              {"0.0.0.0", IPUnknown, "0.0.0.0"},
+		"v6":              {"::", IPUnknown, "::"},
+		"localhost v4":    {"localhost", IPVersion4, "127.0.0.1"},
+		"literal ignores": {"127.0.0.1", IPVersion6, "127.0.0.1"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ip, err := resolveIP(context.Background(), test.sourceIP, test.preferredVersion)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if ip.String() != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, ip)
+			}
+		})
+	}
+}
+
+func TestResolveIPLocalhostIsLoopback(t *testing.T) {
+	ip, err := resolveIP(context.Background(), "localhost", IPUnknown)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ip.IsLoopback() {
+		t.Errorf("expected a loopback IP, got '%s'", ip)
+	}
+}
+
+func TestResolveIPUnresolvable(t *testing.T) {
+	_, err := resolveIP(context.Background(), "does-not-exist.invalid", IPUnknown)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestIPFromAddr(t *testing.T) {
+	tests := map[string]struct {
+		addr     net.IPAddr
+		expected string
+	}{
+		"ipv4":       {net.IPAddr{IP: net.ParseIP("192.0.2.1")}, "192.0.2.1"},
+		"ipv6":       {net.IPAddr{IP: net.ParseIP("2001:db8::1")}, "2001:db8::1"},
+		"link-local": {net.IPAddr{IP: net.ParseIP("fe80::1"), Zone: "eth0"}, "fe80::1%eth0"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ip, ok := ipFromAddr(test.addr)
+			if !ok || ip.String() != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, ip)
+			}
+		})
+	}
+}
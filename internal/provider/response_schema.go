@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const JSONTypeString = "string"
+const JSONTypeNumber = "number"
+const JSONTypeBoolean = "boolean"
+const JSONTypeObject = "object"
+const JSONTypeArray = "array"
+const JSONTypeNull = "null"
+
+var jsonTypes = []string{JSONTypeString, JSONTypeNumber, JSONTypeBoolean, JSONTypeObject, JSONTypeArray, JSONTypeNull}
+
+// responseSchemaMismatch describes a field of the response that does not conform to the response_schema.
+type responseSchemaMismatch struct {
+	field    string
+	expected string
+	actual   string
+}
+
+func (m responseSchemaMismatch) String() string {
+	if m.actual == "" {
+		return fmt.Sprintf("The field '%s' of type '%s' is missing in the response.", m.field, m.expected)
+	}
+	return fmt.Sprintf("The field '%s' is expected to be of type '%s', but is of type '%s'.", m.field, m.expected, m.actual)
+}
+
+// validateResponseSchema checks that every field of the schema is present in the payload and of the expected JSON type.
+// The mismatches are ordered by the name of the field.
+func validateResponseSchema(payload map[string]interface{}, schema map[string]string) []responseSchemaMismatch {
+	var mismatches []responseSchemaMismatch
+	for field, expected := range schema {
+		value, ok := payload[field]
+		if !ok {
+			mismatches = append(mismatches, responseSchemaMismatch{field: field, expected: expected})
+			continue
+		}
+
+		actual := jsonType(value)
+		if actual != expected {
+			mismatches = append(mismatches, responseSchemaMismatch{field: field, expected: expected, actual: actual})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].field < mismatches[j].field
+	})
+	return mismatches
+}
+
+// jsonType returns the JSON type of a value decoded with json.Decoder.UseNumber.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return JSONTypeString
+	case json.Number, float64:
+		return JSONTypeNumber
+	case bool:
+		return JSONTypeBoolean
+	case map[string]interface{}:
+		return JSONTypeObject
+	case []interface{}:
+		return JSONTypeArray
+	case nil:
+		return JSONTypeNull
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateResponseSchema(t *testing.T) {
+	schema := map[string]string{
+		"ip":         JSONTypeString,
+		"asn":        JSONTypeString,
+		"latitude":   JSONTypeNumber,
+		"country_eu": JSONTypeBoolean,
+	}
+
+	tests := map[string]struct {
+		body     string
+		expected []string
+	}{
+		"conforming": {
+			`{"ip":"203.0.113.4","asn":"AS64496","latitude":47.5,"country_eu":false,"extra":[]}`,
+			nil,
+		},
+		"non-conforming": {
+			`{"ip":"203.0.113.4","asn":64496,"country_eu":"no"}`,
+			[]string{
+				"The field 'asn' is expected to be of type 'string', but is of type 'number'.",
+				"The field 'country_eu' is expected to be of type 'boolean', but is of type 'string'.",
+				"The field 'latitude' of type 'number' is missing in the response.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			payload, err := decodePayload(strings.NewReader(test.body), "")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			mismatches := validateResponseSchema(payload, schema)
+			if len(mismatches) != len(test.expected) {
+				t.Fatalf("expected %d mismatches, got %v", len(test.expected), mismatches)
+			}
+			for i, mismatch := range mismatches {
+				if mismatch.String() != test.expected[i] {
+					t.Errorf("expected '%s', got '%s'", test.expected[i], mismatch)
+				}
+			}
+		})
+	}
+}
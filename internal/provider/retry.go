@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// retryBudget limits the total number of retries of all data sources, which share the provider.
+// Once it is exhausted, failing requests are not retried anymore.
+// This protects the IP information provider during widespread failures.
+type retryBudget struct {
+	mu        sync.Mutex
+	remaining int64
+}
+
+func newRetryBudget(retries int64) *retryBudget {
+	return &retryBudget{remaining: retries}
+}
+
+// take consumes a retry from the budget. It returns false if the budget is exhausted.
+func (b *retryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining <= 0 {
+		return false
+	}
+
+	b.remaining--
+	return true
+}
+
+// retryDelay returns the delay before the retry after the given (zero-based) attempt. The delay grows exponentially
+// from minDelay up to maxDelay. A random jitter of up to half the delay spreads the retries of concurrent data sources.
+func retryDelay(attempt int64, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := int64(0); i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay - time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// retryHint tells whether and when a failed request may be retried.
+type retryHint struct {
+	// transient is set if the failure may not occur again, see isTransientError.
+	transient bool
+	// noRoute is set if there is no network route to the IP information provider, see isNoRouteError.
+	noRoute bool
+	// after is the delay requested by the Retry-After header of the response, if any.
+	after time.Duration
+}
+
+// connectivity reports whether the failure is transient or caused by a missing network route,
+// i.e. whether the request may succeed over the other IP stack.
+func (h retryHint) connectivity() bool {
+	return h.transient || h.noRoute
+}
+
+// join combines the hints of several failed requests, e.g. to several IP information providers.
+func (h retryHint) join(other retryHint) retryHint {
+	joined := retryHint{transient: h.transient || other.transient, noRoute: h.noRoute || other.noRoute, after: h.after}
+	if other.after > joined.after {
+		joined.after = other.after
+	}
+	return joined
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if date.Before(now) {
+		return 0, true
+	}
+	return date.Sub(now), true
+}
+
+// retryAfter holds back the requests of all data sources, which share the provider, to an IP information provider
+// until the time requested by its Retry-After header. It is keyed by the host of the IP information provider, so the
+// fallback provider_urls and data sources with another provider_url are not held back. It complements the rate limiter.
+type retryAfter struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// delay holds back the requests to the host until the given time, unless they are held back longer already.
+func (r *retryAfter) delay(host string, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.until == nil {
+		r.until = map[string]time.Time{}
+	}
+	if until.After(r.until[host]) {
+		r.until[host] = until
+	}
+}
+
+// wait blocks until the requests to the host are not held back anymore or the context is done.
+func (r *retryAfter) wait(ctx context.Context, host string) error {
+	r.mu.Lock()
+	delay := time.Until(r.until[host])
+	r.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	log.Printf("waiting for the Retry-After of '%s' ⏳: %s", host, delay)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(2)
+
+	for i, expected := range []bool{true, true, false, false} {
+		if actual := budget.take(); actual != expected {
+			t.Errorf("take #%d: expected %t, got %t", i, expected, actual)
+		}
+	}
+}
+
+func TestRetryBudgetSharedAcrossDataSources(t *testing.T) {
+	var requests int64
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+		"max_retries":      tftypes.NewValue(tftypes.Number, 3),
+		"retry_budget":     tftypes.NewValue(tftypes.Number, 5),
+	})
+
+	const readers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+			_, diags := fetchIPFromProviders(context.Background(), providerData, client, nil)
+			if !diags.HasError() {
+				t.Errorf("expected an error")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests != readers+5 {
+		t.Errorf("expected %d requests, i.e. 5 retries in total, got %d", readers+5, requests)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt, expected := range map[int64]time.Duration{
+		0:   100 * time.Millisecond,
+		1:   200 * time.Millisecond,
+		2:   400 * time.Millisecond,
+		3:   500 * time.Millisecond,
+		100: 500 * time.Millisecond,
+	} {
+		delay := retryDelay(attempt, 100*time.Millisecond, 500*time.Millisecond)
+		if delay > expected || delay < expected/2 {
+			t.Errorf("attempt %d: expected a delay between %s and %s, got %s", attempt, expected/2, expected, delay)
+		}
+	}
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	for status, expectedRequests := range map[int]int64{
+		http.StatusServiceUnavailable: 3,
+		http.StatusNotFound:           1,
+	} {
+		var requests int64
+		server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt64(&requests, 1)
+			w.WriteHeader(status)
+		})
+
+		providerData := testProviderData(t, map[string]tftypes.Value{
+			"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+			"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+			"max_retries":      tftypes.NewValue(tftypes.Number, 2),
+			"retry_min_delay":  tftypes.NewValue(tftypes.String, "1ms"),
+		})
+		client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+		_, diags := fetchIPFromProviders(context.Background(), providerData, client, nil)
+		if !diags.HasError() {
+			t.Errorf("expected an error for the status %d", status)
+		}
+		if requests != expectedRequests {
+			t.Errorf("expected %d requests for the status %d, got %d", expectedRequests, status, requests)
+		}
+	}
+}
+
+func TestRetryHintJoin(t *testing.T) {
+	unavailable := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	notFound := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+	})
+	client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+
+	// The failures of all IP information providers are combined, so a single transient failure may be resolved by the other IP stack.
+	for _, test := range []struct {
+		servers      []string
+		connectivity bool
+	}{
+		{[]string{notFound.URL}, false},
+		{[]string{unavailable.URL}, true},
+		{[]string{notFound.URL, unavailable.URL}, true},
+	} {
+		var providerURLs []*url.URL
+		for _, server := range test.servers {
+			providerURL, _ := url.Parse(server)
+			providerURLs = append(providerURLs, providerURL)
+		}
+
+		_, hint, diags := fetchIPFromURLs(context.Background(), providerData, client, providerURLs, nil)
+		if !diags.HasError() {
+			t.Errorf("expected an error for %v", test.servers)
+		}
+		if hint.connectivity() != test.connectivity {
+			t.Errorf("expected connectivity %t for %v, got %+v", test.connectivity, test.servers, hint)
+		}
+	}
+
+	if joined := (retryHint{noRoute: true}).join(retryHint{after: time.Second}); !joined.connectivity() || joined.after != time.Second {
+		t.Errorf("expected the joined hint to keep the missing route and the Retry-After, got %+v", joined)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		expected time.Duration
+		ok       bool
+	}{
+		"":                              {0, false},
+		"2":                             {2 * time.Second, true},
+		"-1":                            {0, false},
+		"Tue, 01 Nov 2022 12:00:30 GMT": {30 * time.Second, true},
+		"Tue, 01 Nov 2022 11:00:00 GMT": {0, true},
+		"soon":                          {0, false},
+	}
+
+	for value, test := range tests {
+		after, ok := parseRetryAfter(value, now)
+		if after != test.expected || ok != test.ok {
+			t.Errorf("expected %s, %t for '%s', got %s, %t", test.expected, test.ok, value, after, ok)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for retryAfter, expectedRequests := range map[string]int64{
+		"1":   2,
+		"120": 1,
+	} {
+		var requests int64
+		server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt64(&requests, 1) == 1 {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+		})
+
+		providerData := testProviderData(t, map[string]tftypes.Value{
+			"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+			"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+		})
+		client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+		start := time.Now()
+		_, diags := fetchIPFromProviders(context.Background(), providerData, client, nil)
+		if diags.HasError() != (expectedRequests == 1) {
+			t.Errorf("unexpected diagnostics for the Retry-After '%s': %v", retryAfter, diags)
+		}
+		if requests != expectedRequests {
+			t.Errorf("expected %d requests for the Retry-After '%s', got %d", expectedRequests, retryAfter, requests)
+		}
+		if expectedRequests == 2 && time.Since(start) < time.Second {
+			t.Errorf("expected the retry to wait for the Retry-After '%s', took %s", retryAfter, time.Since(start))
+		}
+	}
+}
+
+func TestTotalTimeout(t *testing.T) {
+	var requests int64
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url":     tftypes.NewValue(tftypes.String, server.URL),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+		"max_retries":      tftypes.NewValue(tftypes.Number, 100),
+		"retry_budget":     tftypes.NewValue(tftypes.Number, 100),
+		"retry_min_delay":  tftypes.NewValue(tftypes.String, "100ms"),
+		"total_timeout":    tftypes.NewValue(tftypes.String, "250ms"),
+	})
+	client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+	start := time.Now()
+	ctx, cancel := withTotalTimeout(context.Background(), providerData)
+	defer cancel()
+	_, diags := fetchIPFromProviders(ctx, providerData, client, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the lookup to end after the total_timeout, took %s", elapsed)
+	}
+	if !diags.HasError() || diags[len(diags)-1].Summary() != "Total timeout exceeded" {
+		t.Errorf("expected the total timeout to be exceeded, got: %v", diags)
+	}
+	if requests >= 100 {
+		t.Errorf("expected the retries to be cut short, got %d requests", requests)
+	}
+}
+
+func TestRetryAfterPerHost(t *testing.T) {
+	// The exhausted quota holds back the requests to this IP information provider for two minutes.
+	heldServer := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_urls": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, heldServer.URL),
+			tftypes.NewValue(tftypes.String, server.URL),
+		}),
+		"rate_limit_burst": tftypes.NewValue(tftypes.Number, 1000),
+		"max_retries":      tftypes.NewValue(tftypes.Number, 0),
+	})
+	client := newHTTPClient(providerData, "tcp", netaddr.IP{})
+	start := time.Now()
+	result, diags := fetchIPFromProviders(context.Background(), providerData, client, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if result.providerURL != server.URL {
+		t.Errorf("expected the fallback '%s', got '%s'", server.URL, result.providerURL)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the fallback not to be held back, took %s", elapsed)
+	}
+}
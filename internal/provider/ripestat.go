@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+
+	"inet.af/netaddr"
+)
+
+const DefaultRIPEstatURL = "https://stat.ripe.net/"
+
+// ripestatSourceApp identifies the provider to RIPEstat, as requested by its terms of use.
+const ripestatSourceApp = "terraform-provider-publicip"
+
+// ripestatPrefix is the announcement of the prefix, which covers an IP.
+type ripestatPrefix struct {
+	announced bool
+	prefix    string
+	asn       int64
+	holder    string
+}
+
+// lookupRIPEstatPrefix looks up the most specific announced prefix covering the IP using the RIPEstat `prefix-overview`.
+func lookupRIPEstatPrefix(ctx context.Context, p *ProviderModel, client *http.Client, ip netaddr.IP) (ripestatPrefix, error) {
+	lookupURL := *p.ripestatURL
+	lookupURL.Path = path.Join(lookupURL.Path, "data", "prefix-overview", "data.json")
+	query := lookupURL.Query()
+	query.Set("resource", ip.String())
+	query.Set("sourceapp", ripestatSourceApp)
+	lookupURL.RawQuery = query.Encode()
+
+	log.Printf("looking up the prefix 🔍: %s", lookupURL.String())
+
+	requestCtx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, "GET", lookupURL.String(), nil)
+	if err != nil {
+		return ripestatPrefix{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", p.nextUserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ripestatPrefix{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ripestatPrefix{}, fmt.Errorf("RIPEstat responded with the status code %d '%s'", resp.StatusCode, resp.Status)
+	}
+
+	var overview struct {
+		Status   string  `json:"status"`
+		Messages [][]any `json:"messages"`
+		Data     struct {
+			Announced bool   `json:"announced"`
+			Resource  string `json:"resource"`
+			ASNs      []struct {
+				ASN    int64  `json:"asn"`
+				Holder string `json:"holder"`
+			} `json:"asns"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, p.maxResponseBytes)).Decode(&overview)
+	if err != nil {
+		return ripestatPrefix{}, err
+	}
+	if overview.Status != "ok" {
+		return ripestatPrefix{}, fmt.Errorf("RIPEstat responded with the status '%s': %v", overview.Status, overview.Messages)
+	}
+
+	result := ripestatPrefix{announced: overview.Data.Announced && len(overview.Data.ASNs) > 0}
+	if result.announced {
+		result.prefix = overview.Data.Resource
+		result.asn = overview.Data.ASNs[0].ASN
+		result.holder = overview.Data.ASNs[0].Holder
+	}
+
+	log.Printf("got prefix ✅: %s: %+v", ip, result)
+	return result, nil
+}
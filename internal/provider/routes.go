@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"inet.af/netaddr"
+)
+
+// defaultRoute is the route, which is used for traffic to the internet.
+type defaultRoute struct {
+	iface string
+	// gateway is zero, if the route has no gateway, e.g. over a point-to-point link.
+	gateway netaddr.IP
+}
+
+// defaultRouteIPv4 returns the default route of IPv4 from the routing table. It's only supported on Linux.
+func defaultRouteIPv4() (defaultRoute, error) {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return defaultRoute{}, fmt.Errorf("the routing table can't be read on this system: %w", err)
+	}
+	defer file.Close()
+	return parseIPv4DefaultRoute(file)
+}
+
+// defaultRouteIPv6 returns the default route of IPv6 from the routing table. It's only supported on Linux.
+func defaultRouteIPv6() (defaultRoute, error) {
+	file, err := os.Open("/proc/net/ipv6_route")
+	if err != nil {
+		return defaultRoute{}, fmt.Errorf("the routing table can't be read on this system: %w", err)
+	}
+	defer file.Close()
+	return parseIPv6DefaultRoute(file)
+}
+
+// parseIPv4DefaultRoute parses the format of `/proc/net/route` and returns the default route with the lowest metric.
+func parseIPv4DefaultRoute(r io.Reader) (defaultRoute, error) {
+	var best defaultRoute
+	bestMetric := uint64(0)
+	found := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ..., the addresses are hex in host byte order.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		gateway, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[6], 10, 64)
+		if err != nil || (found && metric >= bestMetric) {
+			continue
+		}
+
+		best = defaultRoute{iface: fields[0]}
+		if gateway != 0 {
+			var ip [4]byte
+			binary.LittleEndian.PutUint32(ip[:], uint32(gateway))
+			best.gateway = netaddr.IPFrom4(ip)
+		}
+		bestMetric = metric
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return defaultRoute{}, err
+	}
+	if !found {
+		return defaultRoute{}, fmt.Errorf("there is no default route")
+	}
+	return best, nil
+}
+
+// parseIPv6DefaultRoute parses the format of `/proc/net/ipv6_route` and returns the default route with the lowest metric.
+func parseIPv6DefaultRoute(r io.Reader) (defaultRoute, error) {
+	const unspecified = "00000000000000000000000000000000"
+	const rejectFlag = 0x0200
+
+	var best defaultRoute
+	bestMetric := uint64(0)
+	found := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Destination PrefixLength Source SourcePrefixLength NextHop Metric RefCnt Use Flags Iface, all in hex.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] != unspecified || fields[1] != "00" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&rejectFlag != 0 {
+			continue
+		}
+		metric, err := strconv.ParseUint(fields[5], 16, 64)
+		if err != nil || (found && metric >= bestMetric) {
+			continue
+		}
+
+		best = defaultRoute{iface: fields[9]}
+		if fields[4] != unspecified {
+			nextHop, err := hex.DecodeString(fields[4])
+			if err != nil || len(nextHop) != 16 {
+				continue
+			}
+			best.gateway = netaddr.IPFrom16(*(*[16]byte)(nextHop))
+		}
+		bestMetric = metric
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return defaultRoute{}, err
+	}
+	if !found {
+		return defaultRoute{}, fmt.Errorf("there is no default route")
+	}
+	return best, nil
+}
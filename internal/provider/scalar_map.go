@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// scalarMap collapses all known scalar attributes of a data source model into a single map of strings.
+// The attributes are identified by their `tfsdk` tag, null and unknown values are omitted.
+func scalarMap(model interface{}, excluded ...string) types.Map {
+	elems := map[string]attr.Value{}
+
+	value := reflect.Indirect(reflect.ValueOf(model))
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Tag.Get("tfsdk")
+		if name == "" || contains(excluded, name) {
+			continue
+		}
+
+		if str, ok := scalarString(value.Field(i).Interface()); ok {
+			elems[name] = types.String{Value: str}
+		}
+	}
+
+	return types.Map{ElemType: types.StringType, Elems: elems}
+}
+
+// scalarString renders a scalar value the same way Terraform converts it into a string.
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case types.String:
+		return v.Value, !v.Null && !v.Unknown
+	case types.Bool:
+		return strconv.FormatBool(v.Value), !v.Null && !v.Unknown
+	case types.Int64:
+		return strconv.FormatInt(v.Value, 10), !v.Null && !v.Unknown
+	case types.Float64:
+		return strconv.FormatFloat(v.Value, 'f', -1, 64), !v.Null && !v.Unknown
+	case types.Number:
+		if v.Null || v.Unknown || v.Value == nil {
+			return "", false
+		}
+		return v.Value.Text('f', -1), true
+	default:
+		return "", false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
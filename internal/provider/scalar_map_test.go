@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testNullAttributes sets all attributes of the model to null.
+func testNullAttributes(model interface{}) {
+	value := reflect.ValueOf(model).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Field(i); field.Kind() == reflect.Struct {
+			if null := field.FieldByName("Null"); null.IsValid() && null.CanSet() {
+				null.SetBool(true)
+			}
+		}
+	}
+}
+
+func TestScalarMap(t *testing.T) {
+	// Only the attributes set here are not null.
+	var data IpDataSourceModel
+	testNullAttributes(&data)
+	data.ID = types.String{Value: "default$203.0.113.4"}
+	data.IPVersion = types.String{Value: IPVersion4}
+	data.IsIPv4 = types.Bool{Value: true}
+	data.IsIPv6 = types.Bool{Value: false}
+	data.IP = types.String{Value: "203.0.113.4"}
+	data.ASNID = types.String{Value: "AS64496"}
+	data.ASNOrg = types.String{Value: "Example"}
+	data.Latitude = types.Float64{Value: 47.5}
+	data.LatitudeExact = types.String{Value: "47.5"}
+	data.ResponseTimeMS = types.Int64{Value: 42}
+	data.SourceIP = types.String{Value: ""}
+
+	all := scalarMap(data, "id", "all")
+
+	expected := map[string]string{
+		"ip_version":       "v4",
+		"is_ipv4":          "true",
+		"is_ipv6":          "false",
+		"ip":               "203.0.113.4",
+		"asn_id":           "AS64496",
+		"asn_org":          "Example",
+		"latitude":         "47.5",
+		"latitude_exact":   "47.5",
+		"response_time_ms": "42",
+		"source_ip":        "",
+	}
+	if len(all.Elems) != len(expected) {
+		t.Errorf("expected %d elements, got %d: %v", len(expected), len(all.Elems), all.Elems)
+	}
+	for key, value := range expected {
+		elem, ok := all.Elems[key].(types.String)
+		if !ok {
+			t.Errorf("expected key '%s' in %v", key, all.Elems)
+			continue
+		}
+		if elem.Value != value {
+			t.Errorf("expected '%s' for key '%s', got '%s'", value, key, elem.Value)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sshDialer opens connections from a remote host by forwarding the standard input and output of the OpenSSH client
+// to the destination (`ssh -W`). The OpenSSH client is used, so its configuration, agent and known hosts apply.
+type sshDialer struct {
+	host         string
+	port         int64
+	user         string
+	identityFile string
+	options      []string
+	// command creates the command of the OpenSSH client with the arguments.
+	command func(ctx context.Context, args ...string) *exec.Cmd
+}
+
+func (d sshDialer) DialContext(ctx context.Context, _ string, addr string) (net.Conn, error) {
+	args := []string{"-o", "BatchMode=yes", "-W", addr}
+	if d.port != 0 {
+		args = append(args, "-p", strconv.FormatInt(d.port, 10))
+	}
+	if d.user != "" {
+		args = append(args, "-l", d.user)
+	}
+	if d.identityFile != "" {
+		args = append(args, "-i", d.identityFile)
+	}
+	for _, option := range d.options {
+		args = append(args, "-o", option)
+	}
+	args = append(args, "--", d.host)
+
+	log.Printf("connecting over SSH 🔐: %s to %s", d.host, addr)
+
+	// The connection outlives the dial, so it's not bound to the context of the dial.
+	cmd := d.command(context.Background(), args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("the SSH client could not be started: %w", err)
+	}
+
+	return &sshConn{cmd: cmd, stdin: stdin, stdout: stdout, stderr: stderr, addr: addr}, nil
+}
+
+// sshConn is a connection through the standard input and output of the OpenSSH client.
+type sshConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *limitedBuffer
+	addr   string
+
+	waitOnce sync.Once
+}
+
+// wait waits for the SSH client to exit, which also completes its standard error.
+func (c *sshConn) wait() {
+	c.waitOnce.Do(func() { _ = c.cmd.Wait() })
+}
+
+func (c *sshConn) Read(b []byte) (int, error) {
+	n, err := c.stdout.Read(b)
+	if err == io.EOF && n == 0 {
+		// The SSH client reports why the connection failed on its standard error.
+		c.wait()
+		if c.stderr.Len() > 0 {
+			return 0, fmt.Errorf("the SSH connection failed: %s", strings.TrimSpace(c.stderr.String()))
+		}
+	}
+	return n, err
+}
+
+func (c *sshConn) Write(b []byte) (int, error) {
+	return c.stdin.Write(b)
+}
+
+func (c *sshConn) Close() error {
+	_ = c.stdin.Close()
+	if c.cmd.Process != nil {
+		_ = c.cmd.Process.Kill()
+	}
+	c.wait()
+	return nil
+}
+
+func (c *sshConn) LocalAddr() net.Addr {
+	return sshAddr("ssh")
+}
+
+func (c *sshConn) RemoteAddr() net.Addr {
+	return sshAddr(c.addr)
+}
+
+// Deadlines are not supported by pipes, the requests are limited by their context instead.
+func (c *sshConn) SetDeadline(time.Time) error      { return nil }
+func (c *sshConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *sshConn) SetWriteDeadline(time.Time) error { return nil }
+
+type sshAddr string
+
+func (a sshAddr) Network() string { return "ssh" }
+func (a sshAddr) String() string  { return string(a) }
+
+// limitedBuffer keeps the first bytes written to it.
+type limitedBuffer struct {
+	mu     sync.Mutex
+	buffer []byte
+	limit  int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if remaining := b.limit - len(b.buffer); remaining > 0 {
+		if len(p) < remaining {
+			remaining = len(p)
+		}
+		b.buffer = append(b.buffer, p[:remaining]...)
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buffer)
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buffer)
+}
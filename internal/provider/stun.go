@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"inet.af/netaddr"
+)
+
+// STUN (RFC 5389) message types and attributes.
+// CHANGE-REQUEST and OTHER-ADDRESS are defined by RFC 5780 for the NAT behavior discovery.
+const (
+	stunMagicCookie    = 0x2112A442
+	stunHeaderLength   = 20
+	stunBindingRequest = 0x0001
+	stunBindingSuccess = 0x0101
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrChangeRequest    = 0x0003
+	stunAttrXORMappedAddress = 0x0020
+	stunAttrOtherAddress     = 0x802c
+
+	stunChangeIP   = 0x04
+	stunChangePort = 0x02
+)
+
+const DefaultSTUNServer = "stun.l.google.com:19302"
+
+var errSTUNTimeout = errors.New("the STUN server did not respond in time")
+
+// stunResponse is the content of a STUN binding success response.
+type stunResponse struct {
+	// mapped is the address of the request as seen by the STUN server.
+	mapped netaddr.IPPort
+	// other is the alternate address of the STUN server, if it supports RFC 5780.
+	other netaddr.IPPort
+	// from is the address, which the response came from.
+	from netaddr.IPPort
+}
+
+// newSTUNRequest builds a binding request, which optionally asks the STUN server to respond from a different IP and/or port.
+func newSTUNRequest(changeIP bool, changePort bool) ([]byte, [12]byte, error) {
+	var transactionID [12]byte
+	_, err := rand.Read(transactionID[:])
+	if err != nil {
+		return nil, transactionID, err
+	}
+
+	var flags uint32
+	if changeIP {
+		flags |= stunChangeIP
+	}
+	if changePort {
+		flags |= stunChangePort
+	}
+
+	msg := make([]byte, stunHeaderLength, stunHeaderLength+8)
+	binary.BigEndian.PutUint16(msg[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint32(msg[4:8], stunMagicCookie)
+	copy(msg[8:20], transactionID[:])
+	if flags != 0 {
+		msg = binary.BigEndian.AppendUint16(msg, stunAttrChangeRequest)
+		msg = binary.BigEndian.AppendUint16(msg, 4)
+		msg = binary.BigEndian.AppendUint32(msg, flags)
+	}
+	binary.BigEndian.PutUint16(msg[2:4], uint16(len(msg)-stunHeaderLength))
+
+	return msg, transactionID, nil
+}
+
+// parseSTUNResponse parses a binding success response to the request with the transactionID.
+func parseSTUNResponse(msg []byte, transactionID [12]byte) (stunResponse, error) {
+	var response stunResponse
+
+	if len(msg) < stunHeaderLength {
+		return response, fmt.Errorf("the STUN message is too short")
+	}
+	if binary.BigEndian.Uint32(msg[4:8]) != stunMagicCookie || string(msg[8:20]) != string(transactionID[:]) {
+		return response, fmt.Errorf("the STUN message is not a response to the request")
+	}
+	if messageType := binary.BigEndian.Uint16(msg[0:2]); messageType != stunBindingSuccess {
+		return response, fmt.Errorf("the STUN server responded with the message type 0x%04x", messageType)
+	}
+
+	length := int(binary.BigEndian.Uint16(msg[2:4]))
+	if length%4 != 0 {
+		return response, fmt.Errorf("the length %d of the STUN message is not a multiple of 4", length)
+	}
+	if len(msg) < stunHeaderLength+length {
+		return response, fmt.Errorf("the STUN message is truncated")
+	}
+
+	attributes := msg[stunHeaderLength : stunHeaderLength+length]
+	for len(attributes) >= 4 {
+		attrType := binary.BigEndian.Uint16(attributes[0:2])
+		attrLength := int(binary.BigEndian.Uint16(attributes[2:4]))
+		if len(attributes) < 4+attrLength {
+			return response, fmt.Errorf("the STUN attribute 0x%04x is truncated", attrType)
+		}
+		value := attributes[4 : 4+attrLength]
+
+		switch attrType {
+		case stunAttrXORMappedAddress:
+			address, err := parseSTUNAddress(value, msg[4:20])
+			if err != nil {
+				return response, err
+			}
+			response.mapped = address
+		case stunAttrMappedAddress:
+			// Only used by servers, which don't support XOR-MAPPED-ADDRESS.
+			if response.mapped.IsZero() {
+				address, err := parseSTUNAddress(value, nil)
+				if err != nil {
+					return response, err
+				}
+				response.mapped = address
+			}
+		case stunAttrOtherAddress:
+			address, err := parseSTUNAddress(value, nil)
+			if err != nil {
+				return response, err
+			}
+			response.other = address
+		}
+
+		// Attributes are padded to a multiple of 4 bytes.
+		padded := 4 + (attrLength+3)&^3
+		if len(attributes) < padded {
+			return response, fmt.Errorf("the padding of the STUN attribute 0x%04x is truncated", attrType)
+		}
+		attributes = attributes[padded:]
+	}
+
+	if response.mapped.IsZero() {
+		return response, fmt.Errorf("the STUN response contains no mapped address")
+	}
+	return response, nil
+}
+
+// parseSTUNAddress parses a (XOR-)MAPPED-ADDRESS attribute. The xor key is the magic cookie and the transaction id.
+func parseSTUNAddress(value []byte, xor []byte) (netaddr.IPPort, error) {
+	if len(value) < 4 {
+		return netaddr.IPPort{}, fmt.Errorf("the STUN address is too short")
+	}
+
+	family := value[1]
+	port := binary.BigEndian.Uint16(value[2:4])
+	var ipBytes []byte
+	switch {
+	case family == 0x01 && len(value) >= 8:
+		ipBytes = append(ipBytes, value[4:8]...)
+	case family == 0x02 && len(value) >= 20:
+		ipBytes = append(ipBytes, value[4:20]...)
+	default:
+		return netaddr.IPPort{}, fmt.Errorf("the STUN address family 0x%02x is unknown", family)
+	}
+
+	if xor != nil {
+		port ^= uint16(stunMagicCookie >> 16)
+		for i := range ipBytes {
+			ipBytes[i] ^= xor[i]
+		}
+	}
+
+	ip, ok := netaddr.FromStdIP(net.IP(ipBytes))
+	if !ok {
+		return netaddr.IPPort{}, fmt.Errorf("the STUN address is invalid")
+	}
+	return netaddr.IPPortFrom(ip, port), nil
+}
+
+// stunQuery sends a binding request to the STUN server and waits for the response.
+// Responses may come from a different address than the server's, if a change was requested.
+func stunQuery(ctx context.Context, conn *net.UDPConn, server *net.UDPAddr, changeIP bool, changePort bool, timeout time.Duration) (stunResponse, error) {
+	request, transactionID, err := newSTUNRequest(changeIP, changePort)
+	if err != nil {
+		return stunResponse{}, err
+	}
+
+	log.Printf("sending STUN binding request 📨: %s (change IP: %t, change port: %t)", server, changeIP, changePort)
+
+	_, err = conn.WriteToUDP(request, server)
+	if err != nil {
+		return stunResponse{}, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetReadDeadline(deadline)
+	if err != nil {
+		return stunResponse{}, err
+	}
+
+	buffer := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return stunResponse{}, errSTUNTimeout
+		}
+		if err != nil {
+			return stunResponse{}, err
+		}
+
+		response, err := parseSTUNResponse(buffer[:n], transactionID)
+		if err != nil {
+			log.Printf("ignoring STUN message from '%s' 🗑️: %s", from, err)
+			continue
+		}
+
+		response.from, _ = netaddr.FromStdAddr(from.IP, from.Port, from.Zone)
+		log.Printf("got STUN binding response ✅: %s from %s", response.mapped, response.from)
+		return response, nil
+	}
+}
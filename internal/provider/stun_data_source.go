@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type STUNDataSource struct {
+	provider *ProviderModel
+}
+
+func NewSTUNDataSource() datasource.DataSource {
+	return &STUNDataSource{}
+}
+
+func (d STUNDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stun"
+}
+
+func (d STUNDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The current public IP as seen by a STUN (RFC 5389) server. Useful in networks, where HTTP requests to the IP information provider are blocked, but STUN works.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"server": {
+				MarkdownDescription: fmt.Sprintf("The STUN server with its port. Defaults to `%s`.", DefaultSTUNServer),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the returned IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'. Set it to '%s' or '%s' to request the IP of that version.", IPVersion6, IPVersion4, IPUnknown, IPVersion6, IPVersion4),
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP as seen by the STUN server.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"port": {
+				MarkdownDescription: "The UDP port as seen by the STUN server. Behind a NAT, this is the port the NAT has mapped the local port to.",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+		},
+	}, nil
+}
+
+func (d *STUNDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type STUNDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Server    types.String `tfsdk:"server"`
+	IPVersion types.String `tfsdk:"ip_version"`
+	IP        types.String `tfsdk:"ip"`
+	Port      types.Int64  `tfsdk:"port"`
+}
+
+func (d STUNDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data STUNDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	network, ok := stunNetwork(data.IPVersion, resp)
+	if !ok {
+		return
+	}
+
+	server := DefaultSTUNServer
+	if !data.Server.Null {
+		server = data.Server.Value
+	}
+
+	conn, serverAddr, err := dialSTUN(network, server)
+	if err != nil {
+		log.Printf("STUN error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error contacting the STUN server", fmt.Sprintf("The STUN server '%s' could not be contacted: %s", server, err))
+		return
+	}
+	defer conn.Close()
+
+	response, err := stunQuery(ctx, conn, serverAddr, false, false, d.provider.timeout)
+	if err != nil {
+		log.Printf("STUN error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error querying the STUN server", fmt.Sprintf("The STUN server '%s' could not be queried: %s", server, err))
+		return
+	}
+
+	ip := response.mapped.IP().Unmap()
+	data.ID = types.String{Value: ip.String()}
+	data.IP = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+	data.Port = types.Int64{Value: int64(response.mapped.Port())}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// stunNetwork returns the UDP network for the requested ip_version.
+func stunNetwork(requestedVersion types.String, resp *datasource.ReadResponse) (string, bool) {
+	if requestedVersion.Null || requestedVersion.Unknown {
+		return "udp", true
+	}
+
+	if requestedVersion.Value != IPVersion4 && requestedVersion.Value != IPVersion6 {
+		resp.Diagnostics.AddError("Invalid ip_version", fmt.Sprintf("The ip_version '%s' must be either '%s' or '%s'.", requestedVersion.Value, IPVersion4, IPVersion6))
+		return "", false
+	}
+
+	return "udp" + strings.TrimPrefix(requestedVersion.Value, "v"), true
+}
+
+// dialSTUN resolves the STUN server and opens a local UDP socket to talk to it.
+func dialSTUN(network string, server string) (*net.UDPConn, *net.UDPAddr, error) {
+	serverAddr, err := net.ResolveUDPAddr(network, server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if network == "udp" {
+		network = "udp4"
+		if serverAddr.IP.To4() == nil {
+			network = "udp6"
+		}
+	}
+
+	conn, err := net.ListenUDP(network, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, serverAddr, nil
+}
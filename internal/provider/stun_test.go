@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"inet.af/netaddr"
+)
+
+// testSTUNResponse builds a binding success response with the given (XOR-)MAPPED-ADDRESS.
+func testSTUNResponse(request []byte, mapped netaddr.IPPort, xor bool) []byte {
+	msg := make([]byte, stunHeaderLength)
+	binary.BigEndian.PutUint16(msg[0:2], stunBindingSuccess)
+	copy(msg[4:20], request[4:20])
+
+	ip := mapped.IP()
+	var family byte = 0x02
+	ipBytes := ip.As16()
+	value := ipBytes[:]
+	if ip.Is4() {
+		family = 0x01
+		value = value[12:]
+	}
+	port := mapped.Port()
+
+	attrType := uint16(stunAttrMappedAddress)
+	if xor {
+		attrType = stunAttrXORMappedAddress
+		port ^= uint16(stunMagicCookie >> 16)
+		for i := range value {
+			value[i] ^= msg[4+i]
+		}
+	}
+
+	msg = binary.BigEndian.AppendUint16(msg, attrType)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(4+len(value)))
+	msg = append(msg, 0, family)
+	msg = binary.BigEndian.AppendUint16(msg, port)
+	msg = append(msg, value...)
+	binary.BigEndian.PutUint16(msg[2:4], uint16(len(msg)-stunHeaderLength))
+	return msg
+}
+
+// testSTUNServer starts a mock STUN server, which reports the given mapped address.
+func testSTUNServer(t *testing.T, mapped netaddr.IPPort) string {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500)
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			_, _ = conn.WriteToUDP(testSTUNResponse(buffer[:n], mapped, true), from)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestParseSTUNResponse(t *testing.T) {
+	request, transactionID, err := newSTUNRequest(false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, mapped := range []string{"203.0.113.4:54321", "[2001:db8::1]:3478"} {
+		for _, xor := range []bool{true, false} {
+			expected := netaddr.MustParseIPPort(mapped)
+			response, err := parseSTUNResponse(testSTUNResponse(request, expected, xor), transactionID)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if response.mapped != expected {
+				t.Errorf("expected '%s' (xor: %t), got '%s'", expected, xor, response.mapped)
+			}
+		}
+	}
+
+	otherRequest, _, _ := newSTUNRequest(false, false)
+	if _, err := parseSTUNResponse(testSTUNResponse(otherRequest, netaddr.MustParseIPPort("203.0.113.4:1"), true), transactionID); err == nil {
+		t.Errorf("expected an error for a foreign transaction")
+	}
+}
+
+func TestParseSTUNResponseUnpaddedAttribute(t *testing.T) {
+	request, transactionID, err := newSTUNRequest(false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A binding success of 29 bytes, whose only attribute (SOFTWARE) has the length 5 and lacks its padding.
+	msg := make([]byte, stunHeaderLength)
+	binary.BigEndian.PutUint16(msg[0:2], stunBindingSuccess)
+	binary.BigEndian.PutUint16(msg[2:4], 9)
+	copy(msg[4:20], request[4:20])
+	msg = binary.BigEndian.AppendUint16(msg, 0x8022)
+	msg = binary.BigEndian.AppendUint16(msg, 5)
+	msg = append(msg, 0, 1, 2, 3, 4)
+
+	if _, err := parseSTUNResponse(msg, transactionID); err == nil {
+		t.Errorf("expected an error for an unpadded attribute")
+	}
+}
+
+func TestNewSTUNRequestChangeRequest(t *testing.T) {
+	request, _, err := newSTUNRequest(true, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(request) != stunHeaderLength+8 || binary.BigEndian.Uint16(request[2:4]) != 8 {
+		t.Fatalf("unexpected request length %d", len(request))
+	}
+	if binary.BigEndian.Uint16(request[20:22]) != stunAttrChangeRequest || binary.BigEndian.Uint32(request[24:28]) != stunChangeIP|stunChangePort {
+		t.Errorf("unexpected CHANGE-REQUEST: %x", request[20:])
+	}
+}
+
+func TestSTUNQueryTimeout(t *testing.T) {
+	// Nobody responds on this socket.
+	silent, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer silent.Close()
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer conn.Close()
+
+	_, err = stunQuery(context.Background(), conn, silent.LocalAddr().(*net.UDPAddr), false, false, 50*time.Millisecond)
+	if err != errSTUNTimeout {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestSTUNDataSource(t *testing.T) {
+	server := testSTUNServer(t, netaddr.MustParseIPPort("203.0.113.4:54321"))
+
+	providerData := testProviderData(t, nil)
+	resp := testReadDataSource(t, NewSTUNDataSource, providerData, map[string]tftypes.Value{
+		"server": tftypes.NewValue(tftypes.String, server),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data STUNDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.Port.Value != 54321 || data.IPVersion.Value != IPVersion4 {
+		t.Errorf("unexpected mapped address: %+v", data)
+	}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// throttle adjusts the rate limiter to the quota announced by the X-RateLimit-Remaining and X-RateLimit-Reset headers
+// (or their unprefixed RateLimit-* variants) of a response of the IP information provider. The remaining requests are
+// spread until the reset, so that many data sources don't exhaust the quota. The rate limit never exceeds the
+// configured rate_limit_rate. Only the requests to the host are throttled, the other IP information providers keep
+// the configured rate limit. If the quota is exhausted, all requests to the host are held back until the reset.
+func throttle(p *ProviderModel, host string, header http.Header, now time.Time) {
+	remaining, ok := rateLimitHeader(header, "Remaining")
+	if !ok {
+		return
+	}
+	reset, ok := rateLimitHeader(header, "Reset")
+	if !ok {
+		return
+	}
+
+	// The reset is either the number of seconds until the reset or, e.g. for GitHub, the time as Unix timestamp.
+	untilReset := time.Duration(reset) * time.Second
+	if reset > now.Unix()/2 {
+		untilReset = time.Unix(reset, 0).Sub(now)
+	}
+	if untilReset <= 0 {
+		return
+	}
+
+	if remaining == 0 {
+		log.Printf("rate limit quota exhausted ⏳: holding back until the reset in %s", untilReset)
+		p.retryAfter.delay(host, now.Add(untilReset))
+		return
+	}
+
+	limit := rate.Limit(float64(remaining) / untilReset.Seconds())
+	if configured := rate.Every(p.rateLimitRate); configured < limit {
+		limit = configured
+	}
+	if limit != p.hostQuotas.limit(host) {
+		log.Printf("adjusting the rate limit of '%s' ⏳: %d requests remaining until the reset in %s", host, remaining, untilReset)
+		p.hostQuotas.setLimitAt(host, now, limit)
+	}
+}
+
+// hostQuotas holds a rate limiter per host of an IP information provider, which throttle adjusts to its quota.
+// The requests to a host wait for its rate limiter in addition to the rate limiter of the provider.
+type hostQuotas struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// limit returns the rate limit of the host, which is unlimited until its quota is known.
+func (q *hostQuotas) limit(host string) rate.Limit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	limiter, ok := q.limiters[host]
+	if !ok {
+		return rate.Inf
+	}
+	return limiter.Limit()
+}
+
+func (q *hostQuotas) setLimitAt(host string, now time.Time, limit rate.Limit) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.limiters == nil {
+		q.limiters = map[string]*rate.Limiter{}
+	}
+	limiter, ok := q.limiters[host]
+	if !ok {
+		// The request, whose response announced the quota, has used up the burst.
+		limiter = rate.NewLimiter(limit, 1)
+		limiter.AllowN(now, 1)
+		q.limiters[host] = limiter
+		return
+	}
+	limiter.SetLimitAt(now, limit)
+}
+
+// wait blocks until the quota of the host allows another request or the context is done.
+func (q *hostQuotas) wait(ctx context.Context, host string) error {
+	q.mu.Lock()
+	limiter, ok := q.limiters[host]
+	q.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// rateLimitHeader returns the value of the X-RateLimit-<name> or RateLimit-<name> header, if it is a number.
+func rateLimitHeader(header http.Header, name string) (int64, bool) {
+	value := header.Get("X-RateLimit-" + name)
+	if value == "" {
+		value = header.Get("RateLimit-" + name)
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/time/rate"
+)
+
+func TestThrottle(t *testing.T) {
+	now := time.Unix(1667304000, 0)
+	tests := map[string]struct {
+		header   http.Header
+		expected rate.Limit
+		held     bool
+	}{
+		"no headers":     {http.Header{}, rate.Inf, false},
+		"seconds":        {http.Header{"X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {"100"}}, 0.1, false},
+		"unix timestamp": {http.Header{"X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {"1667304100"}}, 0.1, false},
+		"unprefixed":     {http.Header{"Ratelimit-Remaining": {"10"}, "Ratelimit-Reset": {"100"}}, 0.1, false},
+		"plenty":         {http.Header{"X-Ratelimit-Remaining": {"1000"}, "X-Ratelimit-Reset": {"10"}}, 2, false},
+		"exhausted":      {http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"100"}}, rate.Inf, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			providerData := testProviderData(t, map[string]tftypes.Value{})
+			throttle(providerData, "ip.example.com", test.header, now)
+
+			if limit := providerData.hostQuotas.limit("ip.example.com"); limit != test.expected {
+				t.Errorf("expected the limit %v, got %v", test.expected, limit)
+			}
+			if limit := providerData.hostQuotas.limit("other.example.com"); limit != rate.Inf {
+				t.Errorf("expected other hosts not to be throttled, got the limit %v", limit)
+			}
+			if limit := providerData.rateLimiter.Limit(); limit != 2 {
+				t.Errorf("expected the rate limit of the provider to be kept, got %v", limit)
+			}
+			if held := providerData.retryAfter.until["ip.example.com"].After(now); held != test.held {
+				t.Errorf("expected the requests to be held back %t, got %t", test.held, held)
+			}
+		})
+	}
+}
+
+func TestThrottleConsecutiveReads(t *testing.T) {
+	server := testIPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Reset", "2")
+		_, _ = w.Write([]byte(`{"ip":"203.0.113.4"}`))
+	})
+
+	// The throttled rate limit of one request per 2s exceeds the timeout of each attempt.
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"provider_url": tftypes.NewValue(tftypes.String, server.URL),
+		"timeout":      tftypes.NewValue(tftypes.String, "1s"),
+	})
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		resp := testReadDataSource(t, NewIpDataSource, providerData, nil)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("read #%d: unexpected diagnostics: %v", i, resp.Diagnostics)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the second read to be throttled, took %s", elapsed)
+	}
+}
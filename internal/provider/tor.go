@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+
+	"inet.af/netaddr"
+)
+
+// torDNSELZone is the DNS zone of the Tor exit list service (TorDNSEL). It only lists IPv4.
+const torDNSELZone = "dnsel.torproject.org"
+
+// torExitCode is the address, which TorDNSEL returns for Tor exit nodes.
+var torExitCode = netaddr.MustParseIP("127.0.0.2")
+
+// lookupTorExit checks with TorDNSEL whether the IP is a Tor exit node. A missing record means, that it is not.
+func lookupTorExit(ctx context.Context, lookupHost func(ctx context.Context, host string) ([]string, error), ip netaddr.IP) (bool, error) {
+	name := reverseDNSLabels(ip.Unmap()) + "." + torDNSELZone
+
+	log.Printf("checking the Tor exit list 🔍: %s", name)
+
+	addresses, err := lookupHost(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, address := range addresses {
+		if code, err := netaddr.ParseIP(address); err == nil && code == torExitCode {
+			return true, nil
+		}
+	}
+	return false, nil
+}
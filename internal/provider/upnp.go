@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The SSDP multicast address and the search target of UPnP Internet Gateway Devices.
+const (
+	ssdpAddress      = "239.255.255.250:1900"
+	upnpSearchTarget = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+)
+
+// upnpServiceTypes are the services of an Internet Gateway Device, which implement GetExternalIPAddress, in order of preference.
+var upnpServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+var errUPnPNoGateway = errors.New("no UPnP Internet Gateway Device responded")
+
+// discoverUPnPGateway sends a SSDP M-SEARCH to the address and returns the LOCATION of the first device description.
+func discoverUPnPGateway(ctx context.Context, address string, timeout time.Duration) (string, error) {
+	multicast, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddress + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: " + upnpSearchTarget + "\r\n\r\n"
+
+	log.Printf("searching UPnP gateway 🔍: %s", address)
+
+	_, err = conn.WriteToUDP([]byte(search), multicast)
+	if err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetReadDeadline(deadline)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := make([]byte, 2048)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return "", errUPnPNoGateway
+		}
+		if err != nil {
+			return "", err
+		}
+
+		response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buffer[:n])), nil)
+		if err != nil {
+			log.Printf("ignoring SSDP message from '%s' 🗑️: %s", from, err)
+			continue
+		}
+		response.Body.Close()
+
+		location := response.Header.Get("Location")
+		if response.StatusCode != http.StatusOK || location == "" {
+			log.Printf("ignoring SSDP response from '%s' 🗑️: status %d, location '%s'", from, response.StatusCode, location)
+			continue
+		}
+
+		log.Printf("found UPnP gateway ✅: %s", location)
+		return location, nil
+	}
+}
+
+// upnpDevice is the part of a UPnP device description, which is needed to find the WAN connection services.
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// findUPnPService fetches the device description at the location and returns the WAN connection service with its absolute control URL.
+func findUPnPService(ctx context.Context, client *http.Client, location string, maxResponseBytes int64) (upnpService, error) {
+	base, err := url.Parse(location)
+	if err != nil {
+		return upnpService{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return upnpService{}, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return upnpService{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return upnpService{}, fmt.Errorf("the device description responded with the status %d", res.StatusCode)
+	}
+
+	var description struct {
+		URLBase string     `xml:"URLBase"`
+		Device  upnpDevice `xml:"device"`
+	}
+	err = xml.NewDecoder(io.LimitReader(res.Body, maxResponseBytes)).Decode(&description)
+	if err != nil {
+		return upnpService{}, fmt.Errorf("the device description could not be parsed: %w", err)
+	}
+	if description.URLBase != "" {
+		base, err = url.Parse(description.URLBase)
+		if err != nil {
+			return upnpService{}, err
+		}
+	}
+
+	services := map[string]upnpService{}
+	devices := []upnpDevice{description.Device}
+	for len(devices) > 0 {
+		device := devices[0]
+		devices = append(devices[1:], device.Devices...)
+		for _, service := range device.Services {
+			if _, found := services[service.ServiceType]; !found {
+				services[service.ServiceType] = service
+			}
+		}
+	}
+
+	for _, serviceType := range upnpServiceTypes {
+		service, found := services[serviceType]
+		if !found {
+			continue
+		}
+		controlURL, err := base.Parse(strings.TrimSpace(service.ControlURL))
+		if err != nil {
+			return upnpService{}, err
+		}
+		service.ControlURL = controlURL.String()
+		return service, nil
+	}
+	return upnpService{}, fmt.Errorf("the device does not provide any of the services %s", strings.Join(upnpServiceTypes, ", "))
+}
+
+// upnpExternalIP calls the GetExternalIPAddress action of the service.
+func upnpExternalIP(ctx context.Context, client *http.Client, service upnpService, maxResponseBytes int64) (string, error) {
+	body := `<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:GetExternalIPAddress xmlns:u="` + service.ServiceType + `"/></s:Body>` +
+		`</s:Envelope>`
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, service.ControlURL, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+service.ServiceType+`#GetExternalIPAddress"`)
+
+	log.Printf("calling GetExternalIPAddress 📨: %s", service.ControlURL)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	payload, err := readLimited(res.Body, maxResponseBytes)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GetExternalIPAddress responded with the status %d: %s", res.StatusCode, payload)
+	}
+
+	var envelope struct {
+		IP string `xml:"Body>GetExternalIPAddressResponse>NewExternalIPAddress"`
+	}
+	err = xml.Unmarshal(payload, &envelope)
+	if err != nil {
+		return "", fmt.Errorf("the GetExternalIPAddress response could not be parsed: %w", err)
+	}
+	if envelope.IP == "" {
+		return "", fmt.Errorf("the gateway did not report an external IP, it may not be connected")
+	}
+	return strings.TrimSpace(envelope.IP), nil
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+type UPnPDataSource struct {
+	provider *ProviderModel
+	// ssdpAddress is where the M-SEARCH for the gateway is sent to.
+	ssdpAddress string
+}
+
+func NewUPnPDataSource() datasource.DataSource {
+	return &UPnPDataSource{ssdpAddress: ssdpAddress}
+}
+
+func (d UPnPDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_upnp"
+}
+
+func (d UPnPDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "The external (WAN) IP as reported by the local router using UPnP IGD `GetExternalIPAddress`. No request leaves the local network.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"location": {
+				MarkdownDescription: "The URL of the device description of the router, e.g. `http://192.168.1.1:5000/rootDesc.xml`. Defaults to the location, which is discovered with SSDP.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"service_type": {
+				MarkdownDescription: "The UPnP service, which reported the IP, e.g. `urn:schemas-upnp-org:service:WANIPConnection:1`.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The external IP of the router.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip_version": {
+				MarkdownDescription: fmt.Sprintf("Whether the external IP is an IPv6 or IPv4. Expected values: '%s', '%s', '%s'", IPVersion6, IPVersion4, IPUnknown),
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *UPnPDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type UPnPDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Location    types.String `tfsdk:"location"`
+	ServiceType types.String `tfsdk:"service_type"`
+	IP          types.String `tfsdk:"ip"`
+	IPVersion   types.String `tfsdk:"ip_version"`
+}
+
+func (d UPnPDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data UPnPDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location := data.Location.Value
+	if data.Location.Null || data.Location.Unknown {
+		var err error
+		location, err = discoverUPnPGateway(ctx, d.ssdpAddress, d.provider.timeout)
+		if err != nil {
+			log.Printf("SSDP error 🚨: %s", err)
+			resp.Diagnostics.AddError("Error discovering the UPnP gateway", fmt.Sprintf("The router could not be discovered: %s. Make sure UPnP is enabled on the router or set the location.", err))
+			return
+		}
+	}
+
+	// The router is in the local network, so the settings for the IP information provider don't apply.
+	client := &http.Client{Timeout: d.provider.timeout}
+
+	service, err := findUPnPService(ctx, client, location, d.provider.maxResponseBytes)
+	if err != nil {
+		log.Printf("UPnP error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error reading the UPnP device description", fmt.Sprintf("The device description at '%s' could not be used: %s", location, err))
+		return
+	}
+
+	rawIP, err := upnpExternalIP(ctx, client, service, d.provider.maxResponseBytes)
+	if err != nil {
+		log.Printf("UPnP error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error querying the UPnP gateway", fmt.Sprintf("The external IP could not be queried from '%s': %s", service.ControlURL, err))
+		return
+	}
+
+	ip, err := netaddr.ParseIP(rawIP)
+	if err != nil {
+		log.Printf("Could not parse IP '%s' 🚨: %s", rawIP, err)
+		resp.Diagnostics.AddError("Unable to parse IP", fmt.Sprintf("The IP '%s' reported by the UPnP gateway could not be parsed: %s", rawIP, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.Location = types.String{Value: location}
+	data.ServiceType = types.String{Value: service.ServiceType}
+	data.IP = types.String{Value: ip.String()}
+	data.IPVersion = types.String{Value: ipVersion(ip)}
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testUPnPDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+// testUPnPGateway starts a mock Internet Gateway Device, which reports the externalIP.
+func testUPnPGateway(t *testing.T, externalIP string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rootDesc.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testUPnPDescription))
+	})
+	mux.HandleFunc("/ctl/IPConn", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("SOAPAction") != `"urn:schemas-upnp-org:service:WANIPConnection:1#GetExternalIPAddress"` {
+			http.Error(w, "invalid action", http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">
+<NewExternalIPAddress>%s</NewExternalIPAddress>
+</u:GetExternalIPAddressResponse>
+</s:Body></s:Envelope>`, externalIP)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testSSDPServer starts a mock SSDP responder, which announces the location.
+func testSSDPServer(t *testing.T, location string) string {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 2048)
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(string(buffer[:n]), "M-SEARCH") || !strings.Contains(string(buffer[:n]), upnpSearchTarget) {
+				continue
+			}
+			response := "HTTP/1.1 200 OK\r\nST: " + upnpSearchTarget + "\r\nLOCATION: " + location + "\r\n\r\n"
+			_, _ = conn.WriteToUDP([]byte(response), from)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func testUPnPDataSource(ssdpAddress string) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &UPnPDataSource{ssdpAddress: ssdpAddress}
+	}
+}
+
+func TestUPnPDataSourceDiscovery(t *testing.T) {
+	gateway := testUPnPGateway(t, "203.0.113.4")
+	ssdp := testSSDPServer(t, gateway.URL+"/rootDesc.xml")
+
+	providerData := testProviderData(t, nil)
+	resp := testReadDataSource(t, testUPnPDataSource(ssdp), providerData, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data UPnPDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.IP.Value != "203.0.113.4" || data.IPVersion.Value != IPVersion4 {
+		t.Errorf("unexpected IP: %+v", data)
+	}
+	if data.Location.Value != gateway.URL+"/rootDesc.xml" || data.ServiceType.Value != "urn:schemas-upnp-org:service:WANIPConnection:1" {
+		t.Errorf("unexpected gateway: %+v", data)
+	}
+}
+
+func TestUPnPDataSourceDisconnected(t *testing.T) {
+	gateway := testUPnPGateway(t, "")
+
+	providerData := testProviderData(t, nil)
+	resp := testReadDataSource(t, testUPnPDataSource("127.0.0.1:1"), providerData, map[string]tftypes.Value{
+		"location": tftypes.NewValue(tftypes.String, gateway.URL+"/rootDesc.xml"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a gateway without external IP")
+	}
+}
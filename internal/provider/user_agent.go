@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// nextUserAgent returns the User-Agent for the next request.
+// If user_agents is configured, the user agents are used in rotation.
+// The user_agent_comment is appended to any of them.
+func (p *ProviderModel) nextUserAgent() string {
+	userAgent := fmt.Sprintf("%s (%s)", UserAgent, p.version)
+	if len(p.userAgents) > 0 {
+		index := atomic.AddUint64(p.userAgentIndex, 1) - 1
+		userAgent = p.userAgents[index%uint64(len(p.userAgents))]
+	}
+
+	if p.userAgentComment != "" {
+		userAgent = fmt.Sprintf("%s (%s)", userAgent, p.userAgentComment)
+	}
+	return userAgent
+}
@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"inet.af/netaddr"
+)
+
+const DefaultWhoisServer = "whois.iana.org"
+const whoisPort = "43"
+
+// whoisFields are the keys of the parsed fields in the responses of the different registries, in order of preference.
+var whoisFields = map[string][]string{
+	"network":  {"inetnum", "inet6num", "NetRange", "CIDR"},
+	"net_name": {"netname", "NetName"},
+	"country":  {"country", "Country"},
+	"org_name": {"org-name", "OrgName", "descr"},
+}
+
+type WhoisDataSource struct {
+	provider *ProviderModel
+}
+
+func NewWhoisDataSource() datasource.DataSource {
+	return &WhoisDataSource{}
+}
+
+func (d WhoisDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_whois"
+}
+
+func (d WhoisDataSource) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Looks up an IP in the whois database of the responsible regional internet registry.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "An ID, which is only used internally. *Do not use this field in your terraform definitions.*",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"ip": {
+				MarkdownDescription: "The IP to look up.",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"server": {
+				MarkdownDescription: fmt.Sprintf("The whois server, which is asked first, optionally with a port, e.g. `whois.ripe.net`. A referral to another whois server is followed once. Defaults to `%s`.", DefaultWhoisServer),
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"server_used": {
+				MarkdownDescription: "The whois server, which provided the `raw` response.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"raw": {
+				MarkdownDescription: "The unaltered response of the whois server.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"network": {
+				MarkdownDescription: "The registered network, which contains the IP, e.g. `203.0.113.0 - 203.0.113.255`. `null` if the response does not contain it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"net_name": {
+				MarkdownDescription: "The name of the registered network. `null` if the response does not contain it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"country": {
+				MarkdownDescription: "The country code of the registered network. `null` if the response does not contain it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"org_name": {
+				MarkdownDescription: "The organisation, which holds the registered network. `null` if the response does not contain it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (d *WhoisDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*ProviderModel)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderModel, got: %T. Please report this issue to the publicip provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = p
+}
+
+type WhoisDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	IP         types.String `tfsdk:"ip"`
+	Server     types.String `tfsdk:"server"`
+	ServerUsed types.String `tfsdk:"server_used"`
+	Raw        types.String `tfsdk:"raw"`
+	Network    types.String `tfsdk:"network"`
+	NetName    types.String `tfsdk:"net_name"`
+	Country    types.String `tfsdk:"country"`
+	OrgName    types.String `tfsdk:"org_name"`
+}
+
+func (d WhoisDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, cancel := withTotalTimeout(ctx, d.provider)
+	defer cancel()
+
+	var data WhoisDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ip, err := netaddr.ParseIP(data.IP.Value)
+	if err != nil {
+		log.Printf("Could not parse IP '%s' 🚨: %s", data.IP.Value, err)
+		resp.Diagnostics.AddError("Invalid IP", fmt.Sprintf("The ip '%s' could not be parsed: %s", data.IP.Value, err))
+		return
+	}
+
+	server := DefaultWhoisServer
+	if !data.Server.Null {
+		server = data.Server.Value
+	}
+
+	raw, err := queryWhois(ctx, server, ip.String(), d.provider.timeout, d.provider.maxResponseBytes)
+	if err == nil {
+		// IANA only refers to the responsible registry.
+		if referral := whoisField(raw, "refer", "ReferralServer"); referral != "" {
+			server = strings.TrimPrefix(referral, "whois://")
+			raw, err = queryWhois(ctx, server, ip.String(), d.provider.timeout, d.provider.maxResponseBytes)
+		}
+	}
+	if err != nil {
+		log.Printf("Whois error 🚨: %s", err)
+		resp.Diagnostics.AddError("Error querying the whois server", fmt.Sprintf("The whois server '%s' could not be queried for '%s': %s", server, ip, err))
+		return
+	}
+
+	data.ID = types.String{Value: ip.String()}
+	data.ServerUsed = types.String{Value: server}
+	data.Raw = types.String{Value: raw}
+	data.Network = whoisValue(raw, whoisFields["network"]...)
+	data.NetName = whoisValue(raw, whoisFields["net_name"]...)
+	data.Country = whoisValue(raw, whoisFields["country"]...)
+	data.OrgName = whoisValue(raw, whoisFields["org_name"]...)
+
+	log.Printf("got to state update ✅: %+v", data)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// queryWhois sends the query to the whois server (RFC 3912) and returns its response.
+func queryWhois(ctx context.Context, server string, query string, timeout time.Duration, maxResponseBytes int64) (string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, whoisPort)
+	}
+
+	log.Printf("querying whois 🔍: %s: %s", server, query)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	_, err = fmt.Fprintf(conn, "%s\r\n", query)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := readLimited(conn, maxResponseBytes)
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// whoisField returns the value of the first of the keys, which is present in the whois response.
+func whoisField(raw string, keys ...string) string {
+	for _, key := range keys {
+		scanner := bufio.NewScanner(strings.NewReader(raw))
+		for scanner.Scan() {
+			name, value, found := strings.Cut(scanner.Text(), ":")
+			if found && strings.TrimSpace(name) == key && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+func whoisValue(raw string, keys ...string) types.String {
+	if value := whoisField(raw, keys...); value != "" {
+		return types.String{Value: value}
+	}
+	return types.String{Null: true}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testWhoisServer starts a mock whois server, which responds with the response for the received query.
+func testWhoisServer(t *testing.T, response func(query string) string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			query, _ := bufio.NewReader(conn).ReadString('\n')
+			_, _ = fmt.Fprint(conn, response(query))
+			conn.Close()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestWhoisDataSource(t *testing.T) {
+	registry := testWhoisServer(t, func(query string) string {
+		if query != "203.0.113.4\r\n" {
+			t.Errorf("unexpected query '%s'", query)
+		}
+		return "% This is the RIPE Database query service.\n\ninetnum:        203.0.113.0 - 203.0.113.255\nnetname:        EXAMPLE-NET\ndescr:          Example AG\ncountry:        CH\n"
+	})
+	iana := testWhoisServer(t, func(query string) string {
+		return "% IANA WHOIS server\n\nrefer:        " + registry + "\n\ninetnum:      203.0.0.0 - 203.255.255.255\n"
+	})
+
+	providerData := testProviderData(t, nil)
+	resp := testReadDataSource(t, NewWhoisDataSource, providerData, map[string]tftypes.Value{
+		"ip":     tftypes.NewValue(tftypes.String, "203.0.113.4"),
+		"server": tftypes.NewValue(tftypes.String, iana),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data WhoisDataSourceModel
+	resp.State.Get(context.Background(), &data)
+	if data.ServerUsed.Value != registry {
+		t.Errorf("expected the referral '%s' to be followed, got '%s'", registry, data.ServerUsed.Value)
+	}
+	if data.Network.Value != "203.0.113.0 - 203.0.113.255" || data.NetName.Value != "EXAMPLE-NET" || data.Country.Value != "CH" || data.OrgName.Value != "Example AG" {
+		t.Errorf("unexpected fields: %+v", data)
+	}
+}
+
+func TestWhoisField(t *testing.T) {
+	raw := "NetRange:       198.51.100.0 - 198.51.100.255\nCIDR:           198.51.100.0/24\nNetName:        EXAMPLE\nOrgName:        Example, Inc.\nComment:        See: https://example.com\n"
+
+	if value := whoisField(raw, whoisFields["network"]...); value != "198.51.100.0 - 198.51.100.255" {
+		t.Errorf("unexpected network '%s'", value)
+	}
+	if value := whoisField(raw, whoisFields["org_name"]...); value != "Example, Inc." {
+		t.Errorf("unexpected org name '%s'", value)
+	}
+	if value := whoisField(raw, "Comment"); value != "See: https://example.com" {
+		t.Errorf("unexpected comment '%s'", value)
+	}
+	if value := whoisField(raw, whoisFields["country"]...); value != "" {
+		t.Errorf("expected no country, got '%s'", value)
+	}
+}
+
+func TestWhoisDataSourceMaxResponseBytes(t *testing.T) {
+	server := testWhoisServer(t, func(query string) string {
+		return "inetnum:        203.0.113.0 - 203.0.113.255\n"
+	})
+
+	providerData := testProviderData(t, map[string]tftypes.Value{
+		"max_response_bytes": tftypes.NewValue(tftypes.Number, 16),
+	})
+	resp := testReadDataSource(t, NewWhoisDataSource, providerData, map[string]tftypes.Value{
+		"ip":     tftypes.NewValue(tftypes.String, "203.0.113.4"),
+		"server": tftypes.NewValue(tftypes.String, server),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("expected an error for a whois response larger than the max_response_bytes")
+	}
+}